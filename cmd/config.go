@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/k1LoW/git-wt/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configJSONFlag   bool
+	configGlobalFlag bool
+	configBranchFlag string
+)
+
+// configFlags maps config keys to the flags that override them.
+// The root command's flags among them are also accepted by git wt config (see init in root.go).
+var configFlags = map[string][]string{
+	"wt.basedir":            {"basedir"},
+	"wt.dirname":            {"dirname"},
+	"wt.branchtemplate":     {"branchtemplate"},
+	"wt.branchpattern":      {"branchpattern"},
+	"wt.create":             {"create", "no-create"},
+	"wt.remote":             {"remote"},
+	"wt.fetch":              {"fetch"},
+	"wt.forge":              {"forge"},
+	"wt.copyignored":        {"copyignored"},
+	"wt.copyuntracked":      {"copyuntracked"},
+	"wt.copymodified":       {"copymodified"},
+	"wt.nocopy":             {"nocopy"},
+	"wt.copy":               {"copy"},
+	"wt.link":               {"link"},
+	"wt.hardlink":           {"hardlink"},
+	"wt.copyfollowsymlinks": {"copyfollowsymlinks"},
+	"wt.copyfrom":           {"copy-from"},
+	"wt.template":           {"template"},
+	"wt.copysource":         {"copysource"},
+	"wt.portblock":          {"portblock"},
+	"wt.portbase":           {"portbase"},
+	"wt.hook":               {"hook"},
+	"wt.nocd":               {"nocd", "no-switch-directory"},
+	"wt.relative":           {"relative"},
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show the effective configuration",
+	Long: `Show the effective value of every wt.* config key.
+
+For each key, the value is shown with its origin and, where applicable,
+its expanded form (e.g., the resolved basedir path). With --branch, values
+from [wt "<pattern>"] subsections matching the branch are applied. Values from
+the applied profile (--profile or wt.defaultprofile) win over both. The
+subsection a value is set in is shown next to its origin.
+
+Origins:
+  default    Not configured
+  file       Repository config file (.gitwt)
+  system, global, local, worktree, command
+             git config scope
+  flag       Command-line flag (e.g., git wt config --copyignored --hook "make setup");
+             git wt config accepts the flags of git wt that override config
+
+Examples:
+  git wt config                           Show the effective configuration
+  git wt config --json                    Show it as JSON
+  git wt config --branch release/1.0      Show the configuration for a branch
+  git wt config --profile light           Show the configuration with a profile
+  git wt config set wt.basedir ../wt      Set a value in the local git config
+  git wt config set wt.copy .env "*.local"
+                                          Replace all patterns of a list key
+  git wt config unset wt.copy --global    Remove a key from the global git config
+  git wt config set 'wt.release/*.hook' ''
+                                          Disable hooks for release/* branches
+
+Note: When a branch or worktree named "config" exists, 'git wt config' switches to it
+instead. To create a branch named "config", use 'git wt -- config'.`,
+	RunE:         runConfig,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>...",
+	Short: "Set a config value",
+	Long: `Set a config value in the local (or global) git config.
+
+The key and value are validated. All existing values of the key are replaced;
+list keys (e.g., wt.copy, wt.hook) accept multiple values.`,
+	RunE:              runConfigSet,
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeConfigKeys,
+	SilenceUsage:      true,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:               "unset <key>",
+	Short:             "Remove a config value",
+	Long:              `Remove all values of a key from the local (or global) git config.`,
+	RunE:              runConfigUnset,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeConfigKeys,
+	SilenceUsage:      true,
+}
+
+func init() {
+	configCmd.Flags().BoolVar(&configJSONFlag, "json", false, "Output as JSON")
+	configCmd.Flags().StringVar(&configBranchFlag, "branch", "", "Show the configuration for a branch, including [wt \"<pattern>\"] overrides")
+	configCmd.PersistentFlags().BoolVar(&configGlobalFlag, "global", false, "Write to the global git config instead of the local one")
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+func runConfig(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	cfg, err := loadConfigFor(ctx, cmd, configBranchFlag)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	entries := cfg.Entries()
+	for i := range entries {
+		e := &entries[i]
+		if flagChanged(cmd, configFlags[e.Key]...) {
+			e.Origin = git.OriginFlag
+		} else {
+			e.Origin, e.Subsection, err = git.ConfigOriginFor(ctx, e.Key, configBranchFlag, cfg.Profile)
+			if err != nil {
+				return fmt.Errorf("failed to get origin of %s: %w", e.Key, err)
+			}
+			// Hooks from the repository config file are not part of cfg.Hooks (see below)
+			if e.Key == "wt.hook" && e.Origin == git.OriginFile {
+				e.Origin = git.OriginDefault
+			}
+		}
+
+		switch e.Key {
+		case "wt.basedir":
+			e.Expanded, err = git.ExpandBaseDir(ctx, cfg.BaseDir)
+			if err != nil {
+				return fmt.Errorf("failed to expand basedir: %w", err)
+			}
+			if e.Origin != git.OriginFlag {
+				if _, err := checkLegacyBaseDir(ctx, cfg.BaseDir); err != nil {
+					e.Note = strings.SplitN(err.Error(), "\n", 2)[0]
+				}
+			}
+		case "wt.copysource":
+			if cfg.CopySource != "" {
+				e.Expanded, err = git.ExpandBaseDir(ctx, cfg.CopySource)
+				if err != nil {
+					return fmt.Errorf("failed to expand copy source: %w", err)
+				}
+			}
+		case "wt.copyfrom":
+			if cfg.CopyFrom != "" {
+				e.Expanded, err = git.ResolveCopySource(ctx, cfg.CopyFrom)
+				if err != nil {
+					e.Note = err.Error()
+				}
+			}
+		case "wt.defaultprofile":
+			if cfg.Profile != "" {
+				e.Note = fmt.Sprintf("applied profile: %s", cfg.Profile)
+			}
+		}
+	}
+
+	// Hooks from the repository config file are listed separately since they run only once trusted
+	if len(cfg.RepoHooks) > 0 {
+		trusted, err := git.IsHooksTrusted(ctx, cfg.RepoHooks)
+		if err != nil {
+			return fmt.Errorf("failed to check trusted hooks: %w", err)
+		}
+		e := git.ConfigEntry{Key: "wt.hook", Value: cfg.RepoHooks, Origin: git.OriginFile, Note: "trusted"}
+		if !trusted {
+			e.Note = "not trusted (asked before the next worktree is created)"
+		}
+		entries = append(entries, e)
+	}
+
+	if configJSONFlag {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	table := newTable(os.Stdout, []string{"KEY", "VALUE", "ORIGIN", "EXPANDED", "NOTE"})
+	for _, e := range entries {
+		origin := e.Origin
+		if e.Subsection != "" {
+			origin = fmt.Sprintf("%s (%s)", e.Origin, e.Subsection)
+		}
+		if err := table.Append([]string{e.Key, formatConfigValue(e.Value), origin, e.Expanded, e.Note}); err != nil {
+			return fmt.Errorf("failed to append row: %w", err)
+		}
+	}
+	if err := table.Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	return git.SetConfigValues(cmd.Context(), args[0], args[1:], configGlobalFlag)
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	return git.UnsetConfig(cmd.Context(), args[0], configGlobalFlag)
+}
+
+func completeConfigKeys(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var keys []string
+	for _, e := range (git.Config{}).Entries() {
+		keys = append(keys, e.Key)
+	}
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
+
+// formatConfigValue formats a config value for the table output.
+func formatConfigValue(v any) string {
+	switch v := v.(type) {
+	case []string:
+		return strings.Join(v, ", ")
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// flagChanged reports whether any of the flags is set on the command line.
+func flagChanged(cmd *cobra.Command, names ...string) bool {
+	for _, name := range names {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -12,54 +12,23 @@ const bashGitWrapper = `
 git() {
     if [[ "$1" == "wt" ]]; then
         shift
-        local nocd_mode=""
-        local nocd_flag=false
-        # Check wt.nocd config (supports: true, all, create, false)
-        nocd_mode="$(command git config --get wt.nocd 2>/dev/null || true)"
-        local existing_worktrees=""
-        if [[ "$nocd_mode" == "create" ]]; then
-            # Get existing worktree paths before running git wt
-            existing_worktrees=$(command git worktree list --porcelain 2>/dev/null | grep '^worktree ' | cut -d' ' -f2- || true)
-        fi
-        local args=()
-        for arg in "$@"; do
-            if [[ "$arg" == "--nocd" || "$arg" == "--no-switch-directory" ]]; then
-                nocd_flag=true
-            fi
-            args+=("$arg")
-        done
         local result
-        result=$(GIT_WT_SHELL_INTEGRATION=1 command git wt "${args[@]}")
+        result=$(GIT_WT_SHELL_INTEGRATION=1 command git wt "$@")
         local exit_code=$?
         # Get the last line for cd target
         local last_line
         last_line=$(echo "$result" | tail -n 1)
-        if [[ $exit_code -eq 0 && -d "$last_line" ]]; then
+        if [[ $exit_code -eq 0 && "$last_line" == "git-wt:nocd" ]]; then
+            # --nocd or wt.nocd applies (resolved by git wt), print the paths without cd
+            echo "$result" | sed '$d' | while IFS= read -r line; do
+                [[ -n "$line" ]] && echo "$line"
+            done
+        elif [[ $exit_code -eq 0 && -d "$last_line" ]]; then
             # Print all lines except the last (intermediate paths)
             echo "$result" | sed '$d' | while IFS= read -r line; do
                 [[ -n "$line" ]] && echo "$line"
             done
-            # Determine whether to cd
-            local should_cd=true
-            if [[ "$nocd_flag" == "true" ]]; then
-                # --nocd flag always prevents cd
-                should_cd=false
-            elif [[ "$nocd_mode" == "true" || "$nocd_mode" == "all" ]]; then
-                # wt.nocd=true/all prevents cd for all operations
-                should_cd=false
-            elif [[ "$nocd_mode" == "create" ]]; then
-                # wt.nocd=create only prevents cd for new worktrees
-                if echo "$existing_worktrees" | grep -qxF "$last_line"; then
-                    should_cd=true  # existing worktree, allow cd
-                else
-                    should_cd=false  # new worktree, prevent cd
-                fi
-            fi
-            if [[ "$should_cd" == "true" ]]; then
-                cd "$last_line"
-            else
-                echo "$last_line"
-            fi
+            cd "$last_line"
         else
             echo "$result"
             return $exit_code
@@ -92,54 +61,23 @@ const zshGitWrapper = `
 git() {
     if [[ "$1" == "wt" ]]; then
         shift
-        local nocd_mode=""
-        local nocd_flag=false
-        # Check wt.nocd config (supports: true, all, create, false)
-        nocd_mode="$(command git config --get wt.nocd 2>/dev/null || true)"
-        local existing_worktrees=""
-        if [[ "$nocd_mode" == "create" ]]; then
-            # Get existing worktree paths before running git wt
-            existing_worktrees=$(command git worktree list --porcelain 2>/dev/null | grep '^worktree ' | cut -d' ' -f2- || true)
-        fi
-        local args=()
-        for arg in "$@"; do
-            if [[ "$arg" == "--nocd" || "$arg" == "--no-switch-directory" ]]; then
-                nocd_flag=true
-            fi
-            args+=("$arg")
-        done
         local result
-        result=$(GIT_WT_SHELL_INTEGRATION=1 command git wt "${args[@]}")
+        result=$(GIT_WT_SHELL_INTEGRATION=1 command git wt "$@")
         local exit_code=$?
         # Get the last line for cd target
         local last_line
         last_line=$(echo "$result" | tail -n 1)
-        if [[ $exit_code -eq 0 && -d "$last_line" ]]; then
+        if [[ $exit_code -eq 0 && "$last_line" == "git-wt:nocd" ]]; then
+            # --nocd or wt.nocd applies (resolved by git wt), print the paths without cd
+            echo "$result" | sed '$d' | while IFS= read -r line; do
+                [[ -n "$line" ]] && echo "$line"
+            done
+        elif [[ $exit_code -eq 0 && -d "$last_line" ]]; then
             # Print all lines except the last (intermediate paths)
             echo "$result" | sed '$d' | while IFS= read -r line; do
                 [[ -n "$line" ]] && echo "$line"
             done
-            # Determine whether to cd
-            local should_cd=true
-            if [[ "$nocd_flag" == "true" ]]; then
-                # --nocd flag always prevents cd
-                should_cd=false
-            elif [[ "$nocd_mode" == "true" || "$nocd_mode" == "all" ]]; then
-                # wt.nocd=true/all prevents cd for all operations
-                should_cd=false
-            elif [[ "$nocd_mode" == "create" ]]; then
-                # wt.nocd=create only prevents cd for new worktrees
-                if echo "$existing_worktrees" | grep -qxF "$last_line"; then
-                    should_cd=true  # existing worktree, allow cd
-                else
-                    should_cd=false  # new worktree, prevent cd
-                fi
-            fi
-            if [[ "$should_cd" == "true" ]]; then
-                cd "$last_line"
-            else
-                echo "$last_line"
-            fi
+            cd "$last_line"
         else
             echo "$result"
             return $exit_code
@@ -192,51 +130,22 @@ const fishGitWrapper = `
 # Override git command to cd after 'git wt <branch>'
 function git --wraps git
     if test "$argv[1]" = "wt"
-        set -l nocd_flag false
-        # Check wt.nocd config (supports: true, all, create, false)
-        set -l nocd_mode (command git config --get wt.nocd 2>/dev/null)
-        set -l existing_worktrees
-        if test "$nocd_mode" = "create"
-            # Get existing worktree paths before running git wt
-            set existing_worktrees (command git worktree list --porcelain 2>/dev/null | grep '^worktree ' | cut -d' ' -f2-)
-        end
-        for arg in $argv[2..]
-            if string match -q -- "--nocd" "$arg"; or string match -q -- "--no-switch-directory" "$arg"
-                set nocd_flag true
-                break
-            end
-        end
         set -lx GIT_WT_SHELL_INTEGRATION 1
         set -l result (command git wt $argv[2..])
         set -l exit_code $status
         # Get the last line for cd target
         set -l last_line $result[-1]
-        if test $exit_code -eq 0 -a -d "$last_line"
-            # Print all lines except the last (intermediate paths)
+        if test $exit_code -eq 0 -a "$last_line" = "git-wt:nocd"
+            # --nocd or wt.nocd applies (resolved by git wt), print the paths without cd
             for line in $result[1..-2]
                 printf "%s\n" "$line"
             end
-            # Determine whether to cd
-            set -l should_cd true
-            if test "$nocd_flag" = "true"
-                # --nocd flag always prevents cd
-                set should_cd false
-            else if test "$nocd_mode" = "true" -o "$nocd_mode" = "all"
-                # wt.nocd=true/all prevents cd for all operations
-                set should_cd false
-            else if test "$nocd_mode" = "create"
-                # wt.nocd=create only prevents cd for new worktrees
-                if contains -- "$last_line" $existing_worktrees
-                    set should_cd true  # existing worktree, allow cd
-                else
-                    set should_cd false  # new worktree, prevent cd
-                end
-            end
-            if test "$should_cd" = "true"
-                cd "$last_line"
-            else
-                printf "%s\n" "$last_line"
+        else if test $exit_code -eq 0 -a -d "$last_line"
+            # Print all lines except the last (intermediate paths)
+            for line in $result[1..-2]
+                printf "%s\n" "$line"
             end
+            cd "$last_line"
         else
             for line in $result
                 printf "%s\n" "$line"
@@ -286,46 +195,23 @@ const powershellGitWrapper = "" +
 	"function Invoke-Git {\n" +
 	"    if ($args[0] -eq \"wt\") {\n" +
 	"        $wtArgs = $args[1..($args.Length-1)]\n" +
-	"        $nocdFlag = ($wtArgs -contains \"--nocd\") -or ($wtArgs -contains \"--no-switch-directory\")\n" +
-	"        # Check wt.nocd config (supports: true, all, create, false)\n" +
-	"        $nocdMode = & git.exe config --get wt.nocd 2>$null\n" +
-	"        $existingWorktrees = @()\n" +
-	"        if ($nocdMode -eq \"create\") {\n" +
-	"            # Get existing worktree paths before running git wt\n" +
-	"            $existingWorktrees = @(& git.exe worktree list --porcelain 2>$null | Where-Object { $_ -match '^worktree ' } | ForEach-Object { $_ -replace '^worktree ', '' })\n" +
-	"        }\n" +
 	"        $env:GIT_WT_SHELL_INTEGRATION = \"1\"\n" +
 	"        $result = & git.exe wt @wtArgs 2>&1\n" +
 	"        $env:GIT_WT_SHELL_INTEGRATION = $null\n" +
 	"        # Get the last line for cd target\n" +
 	"        $lines = @($result -split \"`n\" | Where-Object { $_ -ne \"\" })\n" +
 	"        $lastLine = $lines[-1]\n" +
-	"        if ($LASTEXITCODE -eq 0 -and (Test-Path $lastLine -PathType Container)) {\n" +
-	"            # Print all lines except the last (intermediate paths)\n" +
+	"        if ($LASTEXITCODE -eq 0 -and $lastLine -eq \"git-wt:nocd\") {\n" +
+	"            # --nocd or wt.nocd applies (resolved by git wt), print the paths without cd\n" +
 	"            if ($lines.Count -gt 1) {\n" +
 	"                $lines[0..($lines.Count-2)] | ForEach-Object { Write-Output $_ }\n" +
 	"            }\n" +
-	"            # Determine whether to cd\n" +
-	"            $shouldCd = $true\n" +
-	"            if ($nocdFlag) {\n" +
-	"                # --nocd flag always prevents cd\n" +
-	"                $shouldCd = $false\n" +
-	"            } elseif ($nocdMode -eq \"true\" -or $nocdMode -eq \"all\") {\n" +
-	"                # wt.nocd=true/all prevents cd for all operations\n" +
-	"                $shouldCd = $false\n" +
-	"            } elseif ($nocdMode -eq \"create\") {\n" +
-	"                # wt.nocd=create only prevents cd for new worktrees\n" +
-	"                if ($existingWorktrees -contains $lastLine) {\n" +
-	"                    $shouldCd = $true  # existing worktree, allow cd\n" +
-	"                } else {\n" +
-	"                    $shouldCd = $false  # new worktree, prevent cd\n" +
-	"                }\n" +
-	"            }\n" +
-	"            if ($shouldCd) {\n" +
-	"                Set-Location $lastLine\n" +
-	"            } else {\n" +
-	"                Write-Output $lastLine\n" +
+	"        } elseif ($LASTEXITCODE -eq 0 -and (Test-Path $lastLine -PathType Container)) {\n" +
+	"            # Print all lines except the last (intermediate paths)\n" +
+	"            if ($lines.Count -gt 1) {\n" +
+	"                $lines[0..($lines.Count-2)] | ForEach-Object { Write-Output $_ }\n" +
 	"            }\n" +
+	"            Set-Location $lastLine\n" +
 	"        } else {\n" +
 	"            Write-Output $result\n" +
 	"            return $LASTEXITCODE\n" +
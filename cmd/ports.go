@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/k1LoW/git-wt/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var portsPruneFlag bool
+
+var portsCmd = &cobra.Command{
+	Use:   "ports [branch|worktree|path]",
+	Short: "Show ports allocated to worktrees",
+	Long: `Show ports allocated to worktrees by the port registry (see wt.portblock).
+
+Without arguments, all allocated port blocks are listed.
+With a worktree, its ports are printed one per line.
+
+Examples:
+  git wt ports                 List ports of all worktrees
+  git wt ports feature-a       Print ports of feature-a
+  git wt ports .               Print ports of the current worktree
+  git wt ports --prune         Free ports of worktrees that no longer exist
+
+Note: When a branch or worktree named "ports" exists, 'git wt ports' switches to it
+instead. To create a branch named "ports", use 'git wt -- ports'.`,
+	RunE:              runPorts,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeBranches,
+	SilenceUsage:      true,
+}
+
+func init() {
+	portsCmd.Flags().BoolVar(&portsPruneFlag, "prune", false, "Free ports of worktrees that no longer exist (e.g., removed with 'git worktree remove')")
+	rootCmd.AddCommand(portsCmd)
+}
+
+func runPorts(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	registry, err := git.OpenPortRegistry(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open port registry: %w", err)
+	}
+
+	worktrees, err := git.ListWorktrees(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	if portsPruneFlag {
+		if len(args) > 0 {
+			return fmt.Errorf("cannot specify a worktree with --prune")
+		}
+		paths := make([]string, 0, len(worktrees))
+		for _, wt := range worktrees {
+			paths = append(paths, wt.Path)
+		}
+		pruned, err := registry.Prune(paths)
+		if err != nil {
+			return fmt.Errorf("failed to prune port registry: %w", err)
+		}
+		for _, p := range pruned {
+			fmt.Printf("Freed ports of %s\n", p)
+		}
+		return nil
+	}
+
+	if len(args) == 1 {
+		wt, err := git.FindWorktreeByBranchOrDir(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to find worktree: %w", err)
+		}
+		if wt == nil {
+			return fmt.Errorf("no worktree found for %q", args[0])
+		}
+		block, ok, err := registry.Lookup(wt.Path)
+		if err != nil {
+			return fmt.Errorf("failed to look up ports: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("no ports allocated to %s", wt.Path)
+		}
+		for _, p := range block.Ports() {
+			fmt.Println(p)
+		}
+		return nil
+	}
+
+	blocks, err := registry.List()
+	if err != nil {
+		return fmt.Errorf("failed to list ports: %w", err)
+	}
+	// Blocks are keyed by canonical paths (e.g., /private/tmp for /tmp on macOS)
+	branches := make(map[string]string, len(worktrees))
+	for _, wt := range worktrees {
+		branches[git.CanonicalPath(wt.Path)] = wt.Branch
+	}
+
+	table := newTable(os.Stdout, []string{"PATH", "BRANCH", "PORTS"})
+	for _, p := range git.SortedPaths(blocks) {
+		ports := make([]string, 0, blocks[p].Size)
+		for _, port := range blocks[p].Ports() {
+			ports = append(ports, strconv.Itoa(port))
+		}
+		if err := table.Append([]string{p, branches[p], strings.Join(ports, ",")}); err != nil {
+			return fmt.Errorf("failed to append row: %w", err)
+		}
+	}
+	if err := table.Render(); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+	return nil
+}
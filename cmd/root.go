@@ -22,14 +22,18 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"bufio"
+	"cmp"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/k1LoW/git-wt/internal/git"
 	"github.com/k1LoW/git-wt/version"
+	"github.com/mattn/go-isatty"
 	"github.com/olekukonko/tablewriter"
 	"github.com/olekukonko/tablewriter/tw"
 	"github.com/spf13/cobra"
@@ -41,19 +45,39 @@ var (
 	initShell       string
 	nocd            bool
 	// Config override flags.
-	basedirFlag       string
-	copyignoredFlag   bool
-	copyuntrackedFlag bool
-	copymodifiedFlag  bool
-	nocopyFlag        []string
+	// Copy-related flags are persistent so that subcommands (e.g., sync) can use them.
+	basedirFlag        string
+	dirnameFlag        string
+	branchTemplateFlag string
+	branchPatternFlag  string
+	createFlag         bool
+	noCreateFlag       bool
+	remoteFlag         string
+	fetchFlag          bool
+	prFlag             int
+	forgeFlag          string
+	copyignoredFlag    bool
+	copyuntrackedFlag  bool
+	copymodifiedFlag   bool
+	nocopyFlag         []string
 	copyFlag           []string
+	linkFlag           []string
+	hardlinkFlag       []string
+	followSymlinksFlag bool
+	copyFromFlag       string
+	templateFlag       []string
+	copySourceFlag     string
+	portBlockFlag      int
+	portBaseFlag       int
 	hookFlag           []string
 	allowDeleteDefault bool
 	relativeFlag       bool
+	verboseFlag        bool
+	profileFlag        string
 )
 
 var rootCmd = &cobra.Command{
-	Use:   "git wt [branch|worktree] [start-point]",
+	Use:   "wt [branch|worktree] [start-point]",
 	Short: "A Git subcommand that makes 'git worktree' simple",
 	Long: `git-wt is a Git subcommand that makes 'git worktree' simple.
 
@@ -61,8 +85,13 @@ Examples:
   git wt                                    List all worktrees
   git wt <branch|worktree|path>              Switch to worktree (create worktree/branch if needed)
   git wt <branch|worktree|path> <start-point>    Create worktree from start-point (e.g., origin/main)
+  git wt --pr <number>                      Fetch a pull request into branch pr/<number> (git wt pr/<number> does too, unless the branch exists)
   git wt -d <branch|worktree|path>...       Delete worktree and branch (safe)
   git wt -D <branch|worktree|path>...       Force delete worktree and branch
+  git wt sync [<branch|worktree|path>...|--all]  Re-copy configured files into existing worktrees
+  git wt watch [<branch|worktree|path>...]  Mirror changes to wt.copy files into other worktrees
+  git wt ports [<branch|worktree|path>]     Show ports allocated to worktrees
+  git wt config [--json] [--branch <name>]  Show the effective configuration and its origins
 
 Note: The default branch (e.g., main, master) is protected from accidental deletion.
       - With worktree: worktree is deleted, but branch is preserved.
@@ -88,11 +117,91 @@ Configuration:
   Configuration is done via git config. All config options can be overridden
   with flags for a single invocation.
 
+  Values follow git's rules (e.g., yes/on/1 are true). Invalid values and
+  unknown wt.* keys are reported as warnings.
+
+  A .gitwt file (git config format) committed at the repository root provides
+  shared defaults. It has the lowest precedence: .gitwt, then system, global and
+  local git config, then flags. Hooks from .gitwt only run after you trust them;
+  the approval is recorded in wt.trustedhooks and asked again when they change.
+  .gitwt can only set wt.basedir, wt.nocd, wt.hook and the copy options
+  wt.copyignored, wt.copyuntracked, wt.copymodified, wt.copy and wt.nocopy;
+  other keys are reported as warnings and ignored.
+
+  Options in a [wt "<pattern>"] subsection apply to branches matching the glob
+  (e.g., [wt "release/*"]; * does not match /). For each option, the most
+  specific matching pattern replaces the global values: exact names win over
+  globs, and globs with more literal characters win over fewer. An empty value
+  clears list options (e.g., "hook =" disables hooks). Flags override both.
+    Example: git config 'wt.release/*.basedir' "~/releases/{gitroot}"
+
+  Options in a [wt "profile.<name>"] subsection form a named profile, applied
+  with --profile <name> or wt.defaultprofile. A profile wins over the global and
+  branch-pattern values of the options it sets, and flags override it.
+    Example: git config wt.profile.light.hook "" && git wt --profile light pr-123
+
   wt.basedir (--basedir)
     Worktree base directory.
-    Supported template variables: {gitroot} (repository root directory name)
+    Supported template variables: {gitroot} (repository root directory name),
+    {host}, {owner}, {repo} (parts of the wt.remote URL), {env:VAR}
     Default: .wt
     Example: git config wt.basedir "../{gitroot}-wt"
+    Example: git config --global wt.basedir "~/wt/{host}/{owner}/{repo}"
+
+  wt.dirname (--dirname)
+    Worktree directory name template, relative to wt.basedir.
+    Supported template variables: {branch}, {branch_flat} (/ replaced by -),
+    {short} (last segment), {date} (YYYYMMDD), {user} (OS user name)
+    An existing directory gets a numeric suffix (e.g., login-2).
+    Default: {branch}
+    Example: git config wt.dirname "{branch_flat}"
+
+  wt.branchtemplate (--branchtemplate)
+    Template of the name of new branches, applied only when creating a branch.
+    Supported template variables: {input} (name given on the command line),
+    {user} (OS user name)
+    Example: git config wt.branchtemplate "{user}/{input}"
+
+  wt.branchpattern (--branchpattern)
+    Regular expression that the names of new branches must match.
+    Example: git config wt.branchpattern '^[a-z]+/[0-9]+-'
+
+  wt.create (--create/-c, --no-create)
+    Whether a branch that does not exist is created.
+    Supported values:
+      - auto (default): Create the branch and its worktree
+      - confirm: Ask before creating (fails when stdin is not a terminal)
+      - never: Fail, suggesting the closest worktrees and branches (e.g., for typos)
+    Note: --create always creates the branch and --no-create never does.
+    Example: git config wt.create never
+
+  wt.remote (--remote)
+    Remote preferred when a branch exists on several remotes. Branches that only
+    exist on remotes are created as local branches tracking them (--track); if
+    several remotes have the branch and none is wt.remote, it is an error.
+    The default branch is read from the HEAD of this remote.
+    Default: origin
+    Example: git config wt.remote upstream
+
+  wt.startpoint (start-point argument)
+    Start point of new branches when no start-point argument is given.
+    Supported template variables: {default} (default branch, see wt.remote),
+    {remote} (wt.remote)
+    Default: HEAD
+    Example: git config wt.startpoint "{remote}/{default}"
+             git config wt.startpoint "@{upstream}"
+
+  wt.fetch (--fetch)
+    Fetch the remote branch of the start point (e.g., origin/main) before
+    creating a new branch from it.
+    Default: false
+
+  wt.forge (--forge)
+    Layout of pull request refs on wt.remote, used by --pr and pr/<number>.
+    Supported values:
+      - github (default): refs/pull/<number>/head
+      - gitlab: refs/merge-requests/<number>/head
+    Example: git config wt.forge gitlab
 
   wt.copyignored (--copyignored)
     Copy .gitignore'd files (e.g., .env) to new worktrees.
@@ -108,7 +217,7 @@ Configuration:
 
   wt.nocopy (--nocopy)
     Patterns for files to exclude from copying (gitignore syntax).
-    Can be specified multiple times.
+    Can be specified multiple times. Also excludes files from wt.link and wt.hardlink.
     Example: git config --add wt.nocopy "*.log"
              git config --add wt.nocopy "vendor/"
 
@@ -119,6 +228,66 @@ Configuration:
     Example: git config --add wt.copy "*.code-workspace"
              git config --add wt.copy ".vscode/"
 
+    Files listed in a committed .worktreeinclude file (gitignore syntax) or
+    with the wt-copy attribute in .gitattributes are also copied.
+    Use --verbose to see which option selected each file.
+
+  wt.link (--link)
+    Patterns for ignored or untracked paths to symlink from the source worktree
+    instead of copying (gitignore syntax). A matching directory is linked as a whole.
+    Can be specified multiple times.
+    Example: git config --add wt.link "node_modules/"
+
+  wt.hardlink (--hardlink)
+    Patterns for ignored or untracked paths to hardlink from the source worktree
+    instead of copying (gitignore syntax). Files in a matching directory are hardlinked.
+    Can be specified multiple times.
+    Note: Precedence is wt.nocopy > wt.link > wt.hardlink > copying.
+    Example: git config --add wt.hardlink ".venv/"
+
+  wt.copyfollowsymlinks (--copyfollowsymlinks)
+    Copy the files symlinks point to instead of the symlinks themselves.
+    By default, symlinks are copied as symlinks, and targets inside the source
+    worktree are rewritten to resolve inside the new worktree.
+    Default: false
+
+  wt.copyfrom (--copy-from)
+    Worktree to copy files from when creating a new worktree.
+    Accepts a branch name, worktree directory name or path, or "main"
+    for the main worktree.
+    Default: the current worktree
+    Example: git config wt.copyfrom main
+
+  wt.copysource (--copysource)
+    Directory whose contents are copied into every new worktree, on top of the files
+    copied from the source worktree (e.g., secrets kept outside the repository).
+    File modes are preserved and wt.nocopy patterns apply.
+    Supported template variables: same as wt.basedir
+    Example: git config wt.copysource "~/.config/wt-secrets/{gitroot}"
+
+  wt.template (--template)
+    Patterns for template files to render into new worktrees with Go text/template
+    (gitignore syntax). The output file name drops the ".tmpl" and then the ".wt"
+    suffix (e.g., .env.wt.tmpl -> .env) and replaces a copied file of that name.
+    Available data: {{.Branch}}, {{.BranchSanitized}}, {{.DirName}}, {{.Index}}, {{.RepoName}},
+                    {{.Ports}} (see wt.portblock)
+    Available functions: add (e.g., {{add 3000 .Index}})
+    Can be specified multiple times.
+    Example: git config --add wt.template "*.wt.tmpl"
+
+  wt.portblock (--portblock)
+    Number of ports to allocate to each new worktree. Each worktree gets a stable,
+    non-overlapping block of ports that is freed when the worktree is deleted
+    with git wt -d/-D. The ports are available to hooks as GIT_WT_PORT (first port),
+    GIT_WT_PORT_0..GIT_WT_PORT_<N-1> and GIT_WT_PORTS (comma-separated), and to
+    wt.template files as {{.Ports}}. Use 'git wt ports' to show allocated ports.
+    Default: 0 (disabled)
+    Example: git config wt.portblock 3
+
+  wt.portbase (--portbase)
+    First port handed out by the port registry.
+    Default: 20000
+
   wt.hook (--hook)
     Commands to run after creating a new worktree.
     Can be specified multiple times. Hooks run in the new worktree directory.
@@ -132,6 +301,7 @@ Configuration:
       - true, all: Never cd to worktree (both new and existing)
       - create: Only prevent cd when creating new worktrees (allow cd to existing)
       - false (default): Always cd to worktree
+    Can be set for branch patterns and profiles like other options.
     Note: --nocd flag always prevents cd regardless of config value.
     Using --nocd with --init disables git() wrapper (wt.nocd config does not).
     Example: git config wt.nocd create
@@ -142,24 +312,74 @@ Configuration:
     subdirectory relative to the repository root (like git diff --relative).
     Falls back to worktree root if the subdirectory does not exist in the worktree.
     Default: false
-    Example: git config wt.relative true`,
+    Example: git config wt.relative true
+
+  wt.defaultprofile (--profile)
+    Profile applied when --profile is not given.
+    Default: (none)
+    Example: git config wt.defaultprofile full`,
 	RunE:              runRoot,
 	Args:              cobra.ArbitraryArgs,
 	ValidArgsFunction: completeBranches,
 	SilenceUsage:      true,
 	Version:           version.Version,
+	Annotations: map[string]string{
+		// Show "git wt" as the command name in usage.
+		cobra.CommandDisplayNameAnnotation: "git wt",
+	},
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	// Read wt.* config once per command
+	ctx := git.WithConfigCache(context.Background())
+	if isCompletionRequest(os.Args[1:]) {
+		// Completion functions load config too, and their output must only contain completions
+		ctx = context.WithValue(ctx, quietConfigWarningsKey{}, true)
+	}
+	unshadowBranch(ctx, os.Args[1:])
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		os.Exit(1)
 	}
 }
 
+// isCompletionRequest reports whether args run cobra's hidden completion command (__complete),
+// which the shell completion scripts call.
+func isCompletionRequest(args []string) bool {
+	return len(args) > 0 && (args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd)
+}
+
+// unshadowBranch removes the subcommand (e.g., sync) that args run when a local branch or worktree
+// has the same name, so that "git wt sync" keeps switching to the branch sync as it did before
+// the subcommand was added.
+func unshadowBranch(ctx context.Context, args []string) {
+	c, _, err := rootCmd.Find(args)
+	if err != nil || c == rootCmd {
+		return
+	}
+	for c.Parent() != rootCmd {
+		c = c.Parent()
+	}
+	if branchOrWorktreeExists(ctx, c.Name()) {
+		rootCmd.RemoveCommand(c)
+	}
+}
+
+// branchOrWorktreeExists reports whether name is a local branch or the branch or directory of a worktree.
+// Errors (e.g., outside a repository) are treated as non-existence.
+func branchOrWorktreeExists(ctx context.Context, name string) bool {
+	if exists, err := git.LocalBranchExists(ctx, name); err == nil && exists {
+		return true
+	}
+	wt, err := git.FindWorktreeByBranchOrDir(ctx, name)
+	return err == nil && wt != nil
+}
+
 func init() {
 	// Disable Cobra's default "completion" subcommand.
 	// git-wt uses its own shell integration via --init flag instead.
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	// Disable Cobra's default "help" subcommand so that "git wt help" switches to a branch named help.
+	rootCmd.SetHelpCommand(&cobra.Command{Hidden: true})
 
 	rootCmd.Flags().BoolVarP(&deleteFlag, "delete", "d", false, "Delete worktree and branch by name or path (safe delete, only if merged)")
 	rootCmd.Flags().BoolVarP(&forceDeleteFlag, "force-delete", "D", false, "Force delete worktree and branch by name or path")
@@ -170,15 +390,48 @@ func init() {
 		panic(err) //nostyle:dontpanic
 	}
 	// Config override flags.
-	rootCmd.Flags().StringVar(&basedirFlag, "basedir", "", "Override wt.basedir config (worktree base directory)")
-	rootCmd.Flags().BoolVar(&copyignoredFlag, "copyignored", false, "Override wt.copyignored config (copy .gitignore'd files)")
-	rootCmd.Flags().BoolVar(&copyuntrackedFlag, "copyuntracked", false, "Override wt.copyuntracked config (copy untracked files)")
-	rootCmd.Flags().BoolVar(&copymodifiedFlag, "copymodified", false, "Override wt.copymodified config (copy modified files)")
-	rootCmd.Flags().StringArrayVar(&nocopyFlag, "nocopy", nil, "Exclude files matching pattern from copying (can be specified multiple times)")
-	rootCmd.Flags().StringArrayVar(&copyFlag, "copy", nil, "Always copy files matching pattern (can be specified multiple times)")
+	// Copy-related flags are persistent so that subcommands (e.g., sync) can use them.
+	rootCmd.PersistentFlags().StringVar(&basedirFlag, "basedir", "", "Override wt.basedir config (worktree base directory)")
+	rootCmd.Flags().StringVar(&dirnameFlag, "dirname", "", "Override wt.dirname config (worktree directory name template, e.g., {branch_flat})")
+	rootCmd.Flags().StringVar(&branchTemplateFlag, "branchtemplate", "", "Override wt.branchtemplate config (template of new branch names, e.g., {user}/{input})")
+	rootCmd.Flags().StringVar(&branchPatternFlag, "branchpattern", "", "Override wt.branchpattern config (regular expression that new branch names must match)")
+	rootCmd.Flags().BoolVarP(&createFlag, "create", "c", false, "Create the branch if it does not exist, without asking (overrides wt.create)")
+	rootCmd.Flags().BoolVar(&noCreateFlag, "no-create", false, "Never create the branch if it does not exist (overrides wt.create)")
+	rootCmd.MarkFlagsMutuallyExclusive("create", "no-create")
+	rootCmd.Flags().StringVar(&remoteFlag, "remote", "", "Override wt.remote config (remote preferred when several remotes have the branch)")
+	rootCmd.Flags().BoolVar(&fetchFlag, "fetch", false, "Override wt.fetch config (fetch the remote branch of the start point before creating a new branch)")
+	rootCmd.Flags().IntVar(&prFlag, "pr", 0, "Check out pull request N from wt.remote into branch pr/N and switch to its worktree")
+	rootCmd.Flags().StringVar(&forgeFlag, "forge", "", "Override wt.forge config (layout of pull request refs: github or gitlab)")
+	rootCmd.PersistentFlags().BoolVar(&copyignoredFlag, "copyignored", false, "Override wt.copyignored config (copy .gitignore'd files)")
+	rootCmd.PersistentFlags().BoolVar(&copyuntrackedFlag, "copyuntracked", false, "Override wt.copyuntracked config (copy untracked files)")
+	rootCmd.PersistentFlags().BoolVar(&copymodifiedFlag, "copymodified", false, "Override wt.copymodified config (copy modified files)")
+	rootCmd.PersistentFlags().StringArrayVar(&nocopyFlag, "nocopy", nil, "Exclude files matching pattern from copying (can be specified multiple times)")
+	rootCmd.PersistentFlags().StringArrayVar(&copyFlag, "copy", nil, "Always copy files matching pattern (can be specified multiple times)")
+	rootCmd.PersistentFlags().StringArrayVar(&linkFlag, "link", nil, "Symlink files or directories matching pattern instead of copying (can be specified multiple times)")
+	rootCmd.PersistentFlags().StringArrayVar(&hardlinkFlag, "hardlink", nil, "Hardlink files or directories matching pattern instead of copying (can be specified multiple times)")
+	rootCmd.PersistentFlags().BoolVar(&followSymlinksFlag, "copyfollowsymlinks", false, "Override wt.copyfollowsymlinks config (copy symlink targets instead of symlinks)")
+	rootCmd.PersistentFlags().StringVar(&copyFromFlag, "copy-from", "", "Override wt.copyfrom config (branch, worktree or path to copy files from, or \"main\")")
+	rootCmd.PersistentFlags().StringVar(&copySourceFlag, "copysource", "", "Override wt.copysource config (directory whose contents are copied into worktrees)")
+	rootCmd.PersistentFlags().StringArrayVar(&templateFlag, "template", nil, "Render template files matching pattern into new worktrees (can be specified multiple times)")
+	rootCmd.Flags().IntVar(&portBlockFlag, "portblock", 0, "Override wt.portblock config (number of ports allocated to each new worktree)")
+	rootCmd.Flags().IntVar(&portBaseFlag, "portbase", git.DefaultPortBase, "Override wt.portbase config (first port of the port registry)")
 	rootCmd.Flags().StringArrayVar(&hookFlag, "hook", nil, "Run command after creating new worktree (can be specified multiple times)")
 	rootCmd.Flags().BoolVar(&allowDeleteDefault, "allow-delete-default", false, "Allow deletion of the default branch (main, master)")
 	rootCmd.Flags().BoolVar(&relativeFlag, "relative", false, "Append current subdirectory to worktree path (like git diff --relative)")
+	rootCmd.PersistentFlags().BoolVar(&verboseFlag, "verbose", false, "Print verbose output to stderr (e.g., how each file was copied)")
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Apply the named config profile (wt.profile.<name>.*) instead of wt.defaultprofile")
+	if err := rootCmd.RegisterFlagCompletionFunc("profile", completeProfiles); err != nil {
+		panic(err) //nostyle:dontpanic
+	}
+	// git wt config also accepts the config override flags that are not persistent,
+	// so that it can show their effect (e.g., git wt config --hook "make setup")
+	for _, names := range configFlags {
+		for _, name := range names {
+			if f := rootCmd.Flags().Lookup(name); f != nil {
+				configCmd.Flags().AddFlag(f)
+			}
+		}
+	}
 }
 
 func runRoot(cmd *cobra.Command, args []string) error {
@@ -189,6 +442,17 @@ func runRoot(cmd *cobra.Command, args []string) error {
 		return runInit(initShell, nocd)
 	}
 
+	// Pull request: same as the pr/<N> target
+	if cmd.Flags().Changed("pr") {
+		if len(args) > 0 || deleteFlag || forceDeleteFlag {
+			return fmt.Errorf("--pr cannot be used with arguments or -d/-D (use pr/%d as the target instead)", prFlag)
+		}
+		if prFlag <= 0 {
+			return fmt.Errorf("invalid pull request number: %d", prFlag)
+		}
+		return handleWorktree(ctx, cmd, git.PRBranch(prFlag), "")
+	}
+
 	// No arguments: list worktrees
 	if len(args) == 0 {
 		return listWorktrees(ctx)
@@ -222,17 +486,56 @@ func runRoot(cmd *cobra.Command, args []string) error {
 	return handleWorktree(ctx, cmd, branch, startPoint)
 }
 
+// quietConfigWarningsKey is the context key that suppresses config warnings (see Execute).
+type quietConfigWarningsKey struct{}
+
 // loadConfig loads config from git config and applies flag overrides.
 func loadConfig(ctx context.Context, cmd *cobra.Command) (git.Config, error) {
-	cfg, err := git.LoadConfig(ctx)
+	return loadConfigFor(ctx, cmd, "")
+}
+
+// loadConfigFor loads config for branch, including the [wt "<pattern>"] overrides
+// matching it and the profile selected with --profile or wt.defaultprofile, and applies
+// flag overrides on top.
+func loadConfigFor(ctx context.Context, cmd *cobra.Command, branch string) (git.Config, error) {
+	cfg, err := git.LoadConfigFor(ctx, branch, profileFlag)
 	if err != nil {
 		return cfg, err
 	}
+	if quiet, _ := ctx.Value(quietConfigWarningsKey{}).(bool); !quiet {
+		for _, w := range git.NewConfigWarnings(ctx, cfg.Warnings) {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+		}
+	}
 
 	// Apply flag overrides
 	if cmd.Flags().Changed("basedir") {
 		cfg.BaseDir = basedirFlag
 	}
+	if cmd.Flags().Changed("dirname") {
+		cfg.DirName = dirnameFlag
+	}
+	if cmd.Flags().Changed("branchtemplate") {
+		cfg.BranchTemplate = branchTemplateFlag
+	}
+	if cmd.Flags().Changed("branchpattern") {
+		cfg.BranchPattern = branchPatternFlag
+	}
+	if cmd.Flags().Changed("create") && createFlag {
+		cfg.Create = git.CreateAuto
+	}
+	if cmd.Flags().Changed("no-create") && noCreateFlag {
+		cfg.Create = git.CreateNever
+	}
+	if cmd.Flags().Changed("remote") {
+		cfg.Remote = remoteFlag
+	}
+	if cmd.Flags().Changed("fetch") {
+		cfg.Fetch = fetchFlag
+	}
+	if cmd.Flags().Changed("forge") {
+		cfg.Forge = forgeFlag
+	}
 	if cmd.Flags().Changed("copyignored") {
 		cfg.CopyIgnored = copyignoredFlag
 	}
@@ -248,16 +551,53 @@ func loadConfig(ctx context.Context, cmd *cobra.Command) (git.Config, error) {
 	if cmd.Flags().Changed("copy") {
 		cfg.Copy = copyFlag
 	}
+	if cmd.Flags().Changed("link") {
+		cfg.Link = linkFlag
+	}
+	if cmd.Flags().Changed("hardlink") {
+		cfg.HardLink = hardlinkFlag
+	}
+	if cmd.Flags().Changed("copyfollowsymlinks") {
+		cfg.FollowSymlinks = followSymlinksFlag
+	}
+	if cmd.Flags().Changed("copy-from") {
+		cfg.CopyFrom = copyFromFlag
+	}
+	if cmd.Flags().Changed("copysource") {
+		cfg.CopySource = copySourceFlag
+	}
+	if cmd.Flags().Changed("template") {
+		cfg.Template = templateFlag
+	}
+	if cmd.Flags().Changed("portblock") {
+		cfg.PortBlock = portBlockFlag
+	}
+	if cmd.Flags().Changed("portbase") {
+		cfg.PortBase = portBaseFlag
+	}
 	if cmd.Flags().Changed("hook") {
 		cfg.Hooks = hookFlag
+		cfg.RepoHooks = nil
 	}
 	if cmd.Flags().Changed("relative") {
 		cfg.Relative = relativeFlag
 	}
+	// Also set by the deprecated --no-switch-directory
+	if nocd {
+		cfg.NoCd = git.NoCdAll
+	}
 
 	return cfg, nil
 }
 
+func completeProfiles(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	profiles, err := git.ConfigProfiles(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return profiles, cobra.ShellCompDirectiveNoFileComp
+}
+
 func completeBranches(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	ctx := cmd.Context()
 
@@ -431,33 +771,7 @@ func listWorktrees(ctx context.Context) error {
 		return fmt.Errorf("failed to get current worktree: %w", err)
 	}
 
-	table := tablewriter.NewTable(os.Stdout,
-		tablewriter.WithHeader([]string{"", "PATH", "BRANCH", "HEAD"}),
-		tablewriter.WithHeaderAlignment(tw.AlignLeft),
-		tablewriter.WithHeaderPaddingPerColumn([]tw.Padding{tw.PaddingNone}),
-		tablewriter.WithRowPaddingPerColumn([]tw.Padding{tw.PaddingNone}),
-		tablewriter.WithRendition(tw.Rendition{
-			Borders: tw.Border{
-				Left:   tw.Off,
-				Right:  tw.Off,
-				Top:    tw.Off,
-				Bottom: tw.Off,
-			},
-			Settings: tw.Settings{
-				Separators: tw.Separators{
-					ShowHeader:     tw.Off,
-					ShowFooter:     tw.Off,
-					BetweenRows:    tw.Off,
-					BetweenColumns: tw.Off,
-				},
-				Lines: tw.Lines{
-					ShowTop:        tw.Off,
-					ShowBottom:     tw.Off,
-					ShowHeaderLine: tw.Off,
-					ShowFooterLine: tw.Off,
-				},
-			},
-		}))
+	table := newTable(os.Stdout, []string{"", "PATH", "BRANCH", "HEAD"})
 
 	for _, wt := range worktrees {
 		marker := ""
@@ -482,6 +796,13 @@ func deleteWorktrees(ctx context.Context, branches []string, force bool) error {
 		return fmt.Errorf("failed to get main repository root: %w", err)
 	}
 
+	// Open the port registry before any deletion to free the ports of removed worktrees.
+	// Ports are optional (wt.portblock), so registry errors never block deletion.
+	registry, err := git.OpenPortRegistry(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to open port registry: %v\n", err)
+	}
+
 	// Check if current directory is one of the worktrees being deleted
 	currentWt, err := git.CurrentWorktree(ctx)
 	if err != nil {
@@ -548,6 +869,11 @@ func deleteWorktrees(ctx context.Context, branches []string, force bool) error {
 			if err := git.RemoveWorktree(ctx, wt.Path, force); err != nil {
 				return fmt.Errorf("failed to remove worktree: %w", err)
 			}
+			if registry != nil {
+				if err := registry.Release(wt.Path); err != nil {
+					fmt.Fprintf(os.Stderr, "warning: failed to release ports of %s: %v\n", wt.Path, err)
+				}
+			}
 
 			// Delete branch (only if it exists as a local branch)
 			// Let git branch -d/-D handle the merge check
@@ -614,31 +940,31 @@ func deleteWorktrees(ctx context.Context, branches []string, force bool) error {
 	return nil
 }
 
-func handleWorktree(ctx context.Context, cmd *cobra.Command, branch, startPoint string) error {
-	// Load config with flag overrides
-	cfg, err := loadConfig(ctx, cmd)
+// loadWorktreeConfig loads config for branch with flag overrides, and checks for legacy basedir migration.
+func loadWorktreeConfig(ctx context.Context, cmd *cobra.Command, branch string) (git.Config, error) {
+	cfg, err := loadConfigFor(ctx, cmd, branch)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return cfg, fmt.Errorf("failed to load config: %w", err)
 	}
 
 	// Check for legacy basedir migration (only if --basedir flag is not set)
 	if !cmd.Flags().Changed("basedir") {
 		newBaseDir, err := checkLegacyBaseDir(ctx, cfg.BaseDir)
 		if err != nil {
-			return fmt.Errorf("failed to check legacy basedir: %w", err)
+			return cfg, fmt.Errorf("failed to check legacy basedir: %w", err)
 		}
 		if newBaseDir != "" {
 			cfg.BaseDir = newBaseDir
 		}
 	}
+	return cfg, nil
+}
 
-	// Build copy options from config
-	copyOpts := git.CopyOptions{
-		CopyIgnored:   cfg.CopyIgnored,
-		CopyUntracked: cfg.CopyUntracked,
-		CopyModified:  cfg.CopyModified,
-		NoCopy:        cfg.NoCopy,
-		Copy:          cfg.Copy,
+func handleWorktree(ctx context.Context, cmd *cobra.Command, branch, startPoint string) error {
+	// Load config for the branch with flag overrides
+	cfg, err := loadWorktreeConfig(ctx, cmd, branch)
+	if err != nil {
+		return err
 	}
 
 	// Check if worktree already exists for this branch or directory name
@@ -648,25 +974,166 @@ func handleWorktree(ctx context.Context, cmd *cobra.Command, branch, startPoint
 	}
 
 	if wt != nil {
+		// The query may be a directory name, so use the config for the worktree's branch
+		if wt.Branch != branch && wt.Branch != git.DetachedMarker {
+			cfg, err = loadConfigFor(ctx, cmd, wt.Branch)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+		}
 		// Worktree exists, print path to stdout
 		// start-point is ignored when switching to existing worktree
-		fmt.Println(resolveRelative(ctx, wt.Path, cfg.Relative))
+		printWorktreePath(ctx, wt.Path, cfg, false)
 		return nil
 	}
 
-	// Get worktree path
-	wtPath, err := git.WorktreePathFor(ctx, cfg.BaseDir, branch)
+	// Check if branch exists locally or on a remote
+	var startCommit string
+	exists, remoteBranch, err := findBranch(ctx, branch, cfg.Remote)
 	if err != nil {
-		return fmt.Errorf("failed to get worktree path: %w", err)
+		return fmt.Errorf("failed to check branch: %w", err)
+	}
+
+	// A pr/<N> target checks out pull request N unless a branch of that name exists.
+	// --pr always fetches the pull request, updating the local branch pr/<N> if any.
+	prNumber, isPR := git.ParsePRBranch(branch)
+	isPR = isPR && (cmd.Flags().Changed("pr") || !exists)
+
+	switch {
+	case isPR && exists && remoteBranch == nil:
+		if err := git.FetchPR(ctx, cfg.Remote, cfg.Forge, prNumber); err != nil {
+			return err
+		}
+	case isPR:
+		// Check the new branch before fetching into it. --pr asks for the branch explicitly,
+		// and its name is chosen by git wt, so wt.branchpattern and wt.create do not apply.
+		if err := git.ValidateBranchName(ctx, branch); err != nil {
+			return err
+		}
+		if !cmd.Flags().Changed("pr") {
+			if err := git.CheckBranchPattern(cfg.BranchPattern, branch); err != nil {
+				return err
+			}
+			if err := confirmCreate(ctx, cfg.Create, branch); err != nil {
+				return err
+			}
+		}
+		if err := git.FetchPR(ctx, cfg.Remote, cfg.Forge, prNumber); err != nil {
+			return err
+		}
+		exists, remoteBranch = true, nil
+	case !exists:
+		// Apply the branch template to the name of the new branch only
+		if cfg.BranchTemplate != "" {
+			newBranch, err := git.ExpandBranchTemplate(cfg.BranchTemplate, branch)
+			if err != nil {
+				return err
+			}
+			if newBranch != branch {
+				branch = newBranch
+				// The expanded name may already have a worktree or a branch
+				wt, err := git.FindWorktreeByBranch(ctx, branch)
+				if err != nil {
+					return fmt.Errorf("failed to find worktree: %w", err)
+				}
+				cfg, err = loadWorktreeConfig(ctx, cmd, branch)
+				if err != nil {
+					return err
+				}
+				if wt != nil {
+					printWorktreePath(ctx, wt.Path, cfg, false)
+					return nil
+				}
+				exists, remoteBranch, err = findBranch(ctx, branch, cfg.Remote)
+				if err != nil {
+					return fmt.Errorf("failed to check branch: %w", err)
+				}
+			}
+		}
+		if !exists {
+			// Validate the new branch name before creating anything
+			if err := git.ValidateBranchName(ctx, branch); err != nil {
+				return err
+			}
+			if err := git.CheckBranchPattern(cfg.BranchPattern, branch); err != nil {
+				return err
+			}
+			if err := confirmCreate(ctx, cfg.Create, branch); err != nil {
+				return err
+			}
+
+			// The start-point argument wins over wt.startpoint
+			if startPoint == "" && cfg.StartPoint != "" {
+				startPoint, err = git.ExpandStartPoint(ctx, cfg.StartPoint, cfg.Remote)
+				if err != nil {
+					return err
+				}
+			}
+			if cfg.Fetch && startPoint != "" {
+				if err := git.FetchStartPoint(ctx, startPoint); err != nil {
+					return err
+				}
+			}
+			// HEAD may be unborn, which git reports when creating the worktree
+			startCommit, err = git.ShortCommit(ctx, cmp.Or(startPoint, "HEAD"))
+			if err != nil && startPoint != "" {
+				return fmt.Errorf("invalid start point: %w", err)
+			}
+		}
 	}
 
-	// Check if branch exists
-	exists, err := git.BranchExists(ctx, branch)
+	// Build copy options from config
+	copyOpts, err := copyOptions(ctx, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to check branch: %w", err)
+		return err
 	}
 
-	if exists {
+	// Resolve the worktree to copy files from
+	copyOpts.SrcRoot, err = git.ResolveCopySource(ctx, cfg.CopyFrom)
+	if err != nil {
+		return fmt.Errorf("failed to resolve copy source: %w", err)
+	}
+
+	// Get worktree path, avoiding existing directories
+	wtPath, err := git.WorktreePathFor(ctx, cfg.BaseDir, cfg.DirName, branch)
+	if err != nil {
+		return fmt.Errorf("failed to get worktree path: %w", err)
+	}
+	resolved, err := git.ResolveWorktreePath(ctx, wtPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve worktree path: %w", err)
+	}
+	if resolved != wtPath {
+		fmt.Fprintf(os.Stderr, "%s already exists, creating the worktree at %s\n", wtPath, resolved)
+		wtPath = resolved
+	}
+
+	// Allocate ports before creating the worktree so that templates can use them
+	var ports git.PortBlock
+	if cfg.PortBlock > 0 {
+		registry, err := git.OpenPortRegistry(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to open port registry: %w", err)
+		}
+		ports, err = registry.Allocate(wtPath, cfg.PortBlock, cfg.PortBase)
+		if err != nil {
+			return fmt.Errorf("failed to allocate ports: %w", err)
+		}
+		defer func() {
+			// Free the ports if the worktree was not created
+			if _, err := os.Stat(wtPath); err != nil {
+				_ = registry.Release(wtPath)
+			}
+		}()
+	}
+
+	if remoteBranch != nil {
+		// Branch only exists on a remote, create a local branch tracking it
+		// start-point is ignored when using existing branch
+		if err := git.AddWorktreeTrackingBranch(ctx, wtPath, *remoteBranch, copyOpts); err != nil {
+			return fmt.Errorf("failed to create worktree tracking %s: %w", remoteBranch, err)
+		}
+	} else if exists {
 		// Branch exists, create worktree with existing branch
 		// start-point is ignored when using existing branch
 		if err := git.AddWorktree(ctx, wtPath, branch, copyOpts); err != nil {
@@ -674,23 +1141,195 @@ func handleWorktree(ctx context.Context, cmd *cobra.Command, branch, startPoint
 		}
 	} else {
 		// Branch doesn't exist, create new branch and worktree
+		if startCommit != "" {
+			fmt.Fprintf(os.Stderr, "Creating branch %s from %s (%s)\n", branch, cmp.Or(startPoint, "HEAD"), startCommit)
+		}
 		if err := git.AddWorktreeWithNewBranch(ctx, wtPath, branch, startPoint, copyOpts); err != nil {
 			return fmt.Errorf("failed to create worktree with new branch: %w", err)
 		}
 	}
 
-	// Run hooks after creating new worktree
-	if err := git.RunHooks(ctx, cfg.Hooks, wtPath, os.Stderr); err != nil {
+	// Run hooks after creating new worktree (repository hooks first, once trusted)
+	hooks, err := trustedRepoHooks(ctx, cfg.RepoHooks)
+	if err != nil {
+		fmt.Println(resolveRelative(ctx, wtPath, cfg.Relative))
+		return err
+	}
+	hooks = append(hooks, cfg.Hooks...)
+	if err := git.RunHooks(ctx, hooks, wtPath, ports.Env(), os.Stderr); err != nil {
 		// Print path but return error so shell integration won't cd
 		fmt.Println(resolveRelative(ctx, wtPath, cfg.Relative))
 		return err
 	}
 
 	// Print path to stdout
-	fmt.Println(resolveRelative(ctx, wtPath, cfg.Relative))
+	printWorktreePath(ctx, wtPath, cfg, true)
 	return nil
 }
 
+// shellNoCdMarker is printed after the worktree path when the shell integration should not cd.
+const shellNoCdMarker = "git-wt:nocd"
+
+// printWorktreePath prints the path of the worktree to stdout. With shell integration
+// (GIT_WT_SHELL_INTEGRATION=1), it is followed by shellNoCdMarker when wt.nocd applies:
+// always for NoCdAll (also set by --nocd), and only for new worktrees for NoCdCreate.
+func printWorktreePath(ctx context.Context, path string, cfg git.Config, created bool) {
+	fmt.Println(resolveRelative(ctx, path, cfg.Relative))
+	if os.Getenv("GIT_WT_SHELL_INTEGRATION") != "1" {
+		return
+	}
+	if cfg.NoCd == git.NoCdAll || (cfg.NoCd == git.NoCdCreate && created) {
+		fmt.Println(shellNoCdMarker)
+	}
+}
+
+// findBranch reports whether branch exists locally or on a remote. When it only exists on a remote,
+// the remote branch to track is returned too (remote is preferred when several remotes have it).
+func findBranch(ctx context.Context, branch, remote string) (bool, *git.RemoteBranch, error) {
+	exists, err := git.LocalBranchExists(ctx, branch)
+	if err != nil || exists {
+		return exists, nil, err
+	}
+	remoteBranch, err := git.FindRemoteBranch(ctx, branch, remote)
+	if err != nil {
+		return false, nil, err
+	}
+	return remoteBranch != nil, remoteBranch, nil
+}
+
+// copyOptions builds copy options from config.
+func copyOptions(ctx context.Context, cfg git.Config) (git.CopyOptions, error) {
+	copyOpts := git.CopyOptions{
+		CopyIgnored:    cfg.CopyIgnored,
+		CopyUntracked:  cfg.CopyUntracked,
+		CopyModified:   cfg.CopyModified,
+		NoCopy:         cfg.NoCopy,
+		Copy:           cfg.Copy,
+		Link:           cfg.Link,
+		HardLink:       cfg.HardLink,
+		FollowSymlinks: cfg.FollowSymlinks,
+		Template:       cfg.Template,
+	}
+	if verboseFlag {
+		copyOpts.Log = os.Stderr
+	}
+	if cfg.CopySource != "" {
+		copySource, err := git.ExpandBaseDir(ctx, cfg.CopySource)
+		if err != nil {
+			return copyOpts, fmt.Errorf("failed to expand copy source: %w", err)
+		}
+		copyOpts.CopySource = copySource
+	}
+	return copyOpts, nil
+}
+
+// confirmCreate checks that branch, which does not exist, may be created according to wt.create.
+// In confirm mode, the user is asked when stdin is a terminal. When the branch may not be created,
+// the error suggests the closest existing names (e.g., for a typo).
+func confirmCreate(ctx context.Context, mode, branch string) error {
+	if mode == git.CreateAuto {
+		return nil
+	}
+	suggestions, err := git.SuggestNames(ctx, branch)
+	if err != nil {
+		return fmt.Errorf("failed to suggest names: %w", err)
+	}
+	interactive := isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())
+	if mode == git.CreateConfirm && interactive {
+		if len(suggestions) > 0 {
+			fmt.Fprintf(os.Stderr, "Did you mean %s?\n", strings.Join(suggestions, ", "))
+		}
+		fmt.Fprintf(os.Stderr, "Branch %q does not exist. Create it? [y/N]: ", branch)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer == "y" || answer == "yes" {
+			return nil
+		}
+		return fmt.Errorf("branch %q was not created", branch)
+	}
+
+	msg := fmt.Sprintf("branch %q does not exist (wt.create is %s", branch, mode)
+	if mode == git.CreateConfirm {
+		msg += " and stdin is not a terminal"
+	}
+	msg += ")"
+	switch len(suggestions) {
+	case 0:
+	case 1:
+		msg += "\n\nDid you mean this?\n\t" + suggestions[0]
+	default:
+		msg += "\n\nDid you mean one of these?\n\t" + strings.Join(suggestions, "\n\t")
+	}
+	return fmt.Errorf("%s\n\nTo create it, run: git wt --create %s", msg, branch)
+}
+
+// trustedRepoHooks returns the hooks from the repository config file if they are trusted.
+// Untrusted hooks are shown and the user is asked to trust them. They are skipped when
+// the prompt is declined or stdin is not a terminal.
+func trustedRepoHooks(ctx context.Context, hooks []string) ([]string, error) {
+	if len(hooks) == 0 {
+		return nil, nil
+	}
+	trusted, err := git.IsHooksTrusted(ctx, hooks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check trusted hooks: %w", err)
+	}
+	if trusted {
+		return hooks, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "%s defines hooks that have not been trusted:\n", git.RepoConfigFile)
+	for _, hook := range hooks {
+		fmt.Fprintf(os.Stderr, "  %s\n", hook)
+	}
+	if !isatty.IsTerminal(os.Stdin.Fd()) && !isatty.IsCygwinTerminal(os.Stdin.Fd()) {
+		fmt.Fprintf(os.Stderr, "Skipping them. To trust them, run: git config wt.trustedhooks %s\n", git.HooksHash(hooks))
+		return nil, nil
+	}
+	fmt.Fprint(os.Stderr, "Trust and run these hooks? [y/N]: ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "y" && answer != "yes" {
+		fmt.Fprintln(os.Stderr, "Skipping hooks from "+git.RepoConfigFile)
+		return nil, nil
+	}
+	if err := git.TrustHooks(ctx, hooks); err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+// newTable returns a borderless table with left-aligned headers.
+func newTable(w io.Writer, header []string) *tablewriter.Table {
+	return tablewriter.NewTable(w,
+		tablewriter.WithHeader(header),
+		tablewriter.WithHeaderAlignment(tw.AlignLeft),
+		tablewriter.WithHeaderPaddingPerColumn([]tw.Padding{tw.PaddingNone}),
+		tablewriter.WithRowPaddingPerColumn([]tw.Padding{tw.PaddingNone}),
+		tablewriter.WithRendition(tw.Rendition{
+			Borders: tw.Border{
+				Left:   tw.Off,
+				Right:  tw.Off,
+				Top:    tw.Off,
+				Bottom: tw.Off,
+			},
+			Settings: tw.Settings{
+				Separators: tw.Separators{
+					ShowHeader:     tw.Off,
+					ShowFooter:     tw.Off,
+					BetweenRows:    tw.Off,
+					BetweenColumns: tw.Off,
+				},
+				Lines: tw.Lines{
+					ShowTop:        tw.Off,
+					ShowBottom:     tw.Off,
+					ShowHeaderLine: tw.Off,
+					ShowFooterLine: tw.Off,
+				},
+			},
+		}))
+}
+
 func resolveRelative(ctx context.Context, wtPath string, relative bool) string {
 	if !relative {
 		return wtPath
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/k1LoW/git-wt/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncAllFlag   bool
+	syncCheckFlag bool
+	syncForceFlag bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [branch|worktree|path]...",
+	Short: "Re-copy configured files into existing worktrees",
+	Long: `Re-copy configured files into existing worktrees.
+
+The files selected by the copy options (wt.copyignored, wt.copyuntracked,
+wt.copymodified, wt.copy, wt.nocopy, wt.link and wt.hardlink) are copied again
+from the copy source (wt.copyfrom, default: the current worktree) into the
+given worktrees, along with the contents of wt.copysource.
+Without arguments, the current worktree is synced, by default from the main
+worktree.
+
+Each file is reported with one of the following statuses:
+  missing    The file does not exist in the worktree (copied)
+  drifted    The file changed in the source since it was copied (copied)
+  modified   The file was changed in the worktree, or differs without a recorded
+             copy (copied only with --force)
+
+The content of copied files is recorded in the git directory of each worktree.
+
+Examples:
+  git wt sync                          Sync the current worktree from the main worktree
+  git wt sync feature-a                Sync copied files into feature-a
+  git wt sync --all                    Sync copied files into all worktrees
+  git wt sync --all --check            Report drifted files without copying
+  git wt sync --copy-from main --all   Sync from the main worktree
+
+Note: When a branch or worktree named "sync" exists, 'git wt sync' switches to it
+instead. To create a branch named "sync", use 'git wt -- sync'.`,
+	RunE:              runSync,
+	Args:              cobra.ArbitraryArgs,
+	ValidArgsFunction: completeBranches,
+	SilenceUsage:      true,
+}
+
+func init() {
+	syncCmd.Flags().BoolVarP(&syncAllFlag, "all", "a", false, "Sync all worktrees except the copy source")
+	syncCmd.Flags().BoolVar(&syncCheckFlag, "check", false, "Only report drifted files; exit with non-zero status if any file is out of sync")
+	syncCmd.Flags().BoolVarP(&syncForceFlag, "force", "f", false, "Overwrite files modified in the target worktrees")
+	rootCmd.AddCommand(syncCmd)
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if syncAllFlag && len(args) > 0 {
+		return fmt.Errorf("cannot specify worktrees with --all")
+	}
+
+	cfg, err := loadConfig(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	srcRoot, err := git.ResolveCopySource(ctx, cfg.CopyFrom)
+	if err != nil {
+		return fmt.Errorf("failed to resolve copy source: %w", err)
+	}
+	switch {
+	case srcRoot != "":
+	case !syncAllFlag && len(args) == 0:
+		// The current worktree is the target, so it cannot be the source too
+		srcRoot, err = git.MainRepoRoot(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get main repository root: %w", err)
+		}
+	default:
+		srcRoot, err = git.RepoRoot(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get repository root: %w", err)
+		}
+	}
+
+	baseDir, err := git.ExpandBaseDir(ctx, cfg.BaseDir)
+	if err != nil {
+		return fmt.Errorf("failed to expand basedir: %w", err)
+	}
+
+	// Resolve target worktrees
+	var targets []git.Worktree
+	switch {
+	case syncAllFlag:
+		worktrees, err := git.ListWorktrees(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list worktrees: %w", err)
+		}
+		for _, wt := range worktrees {
+			if wt.Bare || wt.Path == srcRoot {
+				continue
+			}
+			targets = append(targets, wt)
+		}
+	case len(args) == 0:
+		current, err := git.CurrentWorktree(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get current worktree: %w", err)
+		}
+		targets = append(targets, git.Worktree{Path: current})
+	default:
+		for _, arg := range uniqueArgs(args) {
+			wt, err := git.FindWorktreeByBranchOrDir(ctx, arg)
+			if err != nil {
+				return fmt.Errorf("failed to find worktree: %w", err)
+			}
+			if wt == nil {
+				return fmt.Errorf("no worktree found for %q", arg)
+			}
+			targets = append(targets, *wt)
+		}
+	}
+
+	copyOpts, err := copyOptions(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	syncOpts := git.SyncOptions{
+		Check:   syncCheckFlag,
+		Force:   syncForceFlag,
+		BaseDir: baseDir,
+	}
+
+	outOfSync := 0
+	skipped := 0
+	for _, wt := range targets {
+		if wt.Path == srcRoot {
+			return fmt.Errorf("cannot sync %s: it is the copy source (use --copy-from to choose another source)", wt.Path)
+		}
+		results, err := git.SyncWorktree(ctx, srcRoot, wt.Path, copyOpts, syncOpts)
+		if err != nil {
+			return fmt.Errorf("failed to sync %s: %w", wt.Path, err)
+		}
+		name := filepath.Base(wt.Path)
+		if wt.Branch != "" && wt.Branch != git.DetachedMarker {
+			name = wt.Branch
+		}
+		for _, r := range results {
+			if r.Status == git.SyncUpToDate {
+				continue
+			}
+			outOfSync++
+			switch {
+			case syncCheckFlag:
+				fmt.Printf("%s: %s %s\n", name, r.Status, r.Path)
+			case r.Synced:
+				fmt.Printf("%s: %s %s (synced)\n", name, r.Status, r.Path)
+			default:
+				skipped++
+				fmt.Printf("%s: %s %s (skipped, use --force to overwrite)\n", name, r.Status, r.Path)
+			}
+		}
+	}
+
+	if syncCheckFlag && outOfSync > 0 {
+		return fmt.Errorf("%d file(s) out of sync", outOfSync)
+	}
+	if skipped > 0 {
+		return fmt.Errorf("%d modified file(s) skipped", skipped)
+	}
+	return nil
+}
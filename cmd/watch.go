@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/k1LoW/git-wt/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var watchDebounceFlag int
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [branch|worktree|path]...",
+	Short: "Mirror changes to wt.copy files into other worktrees",
+	Long: `Mirror changes to wt.copy files into other worktrees.
+
+Watches the copy source (wt.copyfrom, default: the current worktree) and copies
+files matching wt.copy patterns into the given worktrees whenever they change,
+until interrupted. Without arguments, changes are mirrored into all other
+worktrees, including worktrees created while watching.
+
+Files matching wt.nocopy patterns and tracked files are never mirrored.
+Deletions are not mirrored. Files that already have the same content in a
+worktree are left untouched, so worktrees can watch each other without loops.
+
+Examples:
+  git wt watch                          Mirror into all other worktrees
+  git wt watch feature-a feature-b      Mirror into selected worktrees
+  git wt watch --copy .env.local        Mirror .env.local only
+
+Note: When a branch or worktree named "watch" exists, 'git wt watch' switches to it
+instead. To create a branch named "watch", use 'git wt -- watch'.`,
+	RunE:              runWatch,
+	Args:              cobra.ArbitraryArgs,
+	ValidArgsFunction: completeBranches,
+	SilenceUsage:      true,
+}
+
+func init() {
+	watchCmd.Flags().IntVar(&watchDebounceFlag, "debounce", int(git.DefaultWatchDebounce.Milliseconds()), "Milliseconds to wait after the last change before mirroring")
+	rootCmd.AddCommand(watchCmd)
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := loadConfig(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	srcRoot, err := git.ResolveCopySource(ctx, cfg.CopyFrom)
+	if err != nil {
+		return fmt.Errorf("failed to resolve copy source: %w", err)
+	}
+	if srcRoot == "" {
+		srcRoot, err = git.RepoRoot(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get repository root: %w", err)
+		}
+	}
+
+	baseDir, err := git.ExpandBaseDir(ctx, cfg.BaseDir)
+	if err != nil {
+		return fmt.Errorf("failed to expand basedir: %w", err)
+	}
+
+	var targets []string
+	for _, arg := range uniqueArgs(args) {
+		wt, err := git.FindWorktreeByBranchOrDir(ctx, arg)
+		if err != nil {
+			return fmt.Errorf("failed to find worktree: %w", err)
+		}
+		if wt == nil {
+			return fmt.Errorf("no worktree found for %q", arg)
+		}
+		if wt.Path == srcRoot {
+			return fmt.Errorf("cannot watch into %s: it is the copy source (use --copy-from to choose another source)", wt.Path)
+		}
+		targets = append(targets, wt.Path)
+	}
+
+	copyOpts, err := copyOptions(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "watching %s (press Ctrl+C to stop)\n", srcRoot)
+	return git.WatchWorktrees(ctx, srcRoot, copyOpts, git.WatchOptions{
+		Targets:  targets,
+		Debounce: time.Duration(watchDebounceFlag) * time.Millisecond,
+		BaseDir:  baseDir,
+		Log:      os.Stderr,
+	})
+}
@@ -1,9 +1,9 @@
 // basic_test.go contains basic functionality tests:
 //   - TestE2E_ListWorktrees: listing worktrees and table formatting
-//   - TestE2E_CreateWorktree: creating worktrees (basic, start-point, existing branch, from worktree)
+//   - TestE2E_CreateWorktree: creating worktrees (basic, start-point, existing branch, from worktree, remote branches, invalid branch names)
 //   - TestE2E_SwitchWorktree: switching to existing worktrees
 //   - TestE2E_SwitchWorktreeByPath: switching to worktrees by filesystem path
-//   - TestE2E_CLI: CLI behavior (version, help, argument validation)
+//   - TestE2E_CLI: CLI behavior (version, help, argument validation, branches named like subcommands)
 package e2e
 
 import (
@@ -408,6 +408,110 @@ func TestE2E_CreateWorktree(t *testing.T) {
 			t.Errorf("second worktree path = %q, want %q", wt2Path, expectedWt2Path)
 		}
 	})
+
+	t.Run("remote_branch_on_any_remote", func(t *testing.T) {
+		t.Parallel()
+		upstream := testutil.NewTestRepo(t)
+		upstream.CreateFile("README.md", "# Upstream")
+		upstream.Commit("initial commit")
+		upstream.Git("checkout", "-b", "feature")
+		upstream.CreateFile("feature.txt", "upstream feature")
+		upstream.Commit("feature commit")
+
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("remote", "add", "upstream", upstream.Root)
+		repo.Git("fetch", "upstream")
+
+		out, err := runGitWt(t, binPath, repo.Root, "feature")
+		if err != nil {
+			t.Fatalf("git-wt feature failed: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+		if _, err := os.Stat(filepath.Join(wtPath, "feature.txt")); err != nil {
+			t.Errorf("worktree should be based on upstream/feature: %v", err)
+		}
+		if got := repo.Git("rev-parse", "--abbrev-ref", "feature@{upstream}"); got != "upstream/feature" {
+			t.Errorf("feature should track upstream/feature, got: %s", got)
+		}
+	})
+
+	t.Run("ambiguous_remote_branch", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		for _, name := range []string{"upstream", "fork"} {
+			remote := testutil.NewTestRepo(t)
+			remote.CreateFile("README.md", "# "+name)
+			remote.Commit("initial commit")
+			remote.Git("branch", "feature")
+			repo.Git("remote", "add", name, remote.Root)
+			repo.Git("fetch", name)
+		}
+
+		out, err := runGitWt(t, binPath, repo.Root, "feature")
+		if err == nil {
+			t.Fatalf("git-wt should fail when several remotes have the branch, got: %s", out)
+		}
+		if !strings.Contains(out, "exists on several remotes (fork, upstream)") || !strings.Contains(out, "wt.remote") {
+			t.Errorf("output should explain the ambiguity and mention wt.remote, got: %s", out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--remote", "fork", "feature")
+		if err != nil {
+			t.Fatalf("git-wt --remote fork feature failed: %v\noutput: %s", err, out)
+		}
+		if got := repo.Git("rev-parse", "--abbrev-ref", "feature@{upstream}"); got != "fork/feature" {
+			t.Errorf("feature should track fork/feature, got: %s", got)
+		}
+	})
+
+	t.Run("invalid_branch_name", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "foo..bar")
+		if err == nil {
+			t.Fatalf("git-wt should fail for an invalid branch name, got: %s", out)
+		}
+		if !strings.Contains(out, `invalid branch name "foo..bar"`) {
+			t.Errorf("output should explain the invalid branch name, got: %s", out)
+		}
+
+		if _, err := os.Stat(filepath.Join(repo.Root, ".wt")); !os.IsNotExist(err) {
+			t.Error("no worktree directory should be created for an invalid branch name")
+		}
+		if branches := repo.Git("branch", "--list", "foo*"); branches != "" {
+			t.Errorf("no branch should be created for an invalid branch name, got: %s", branches)
+		}
+	})
+
+	t.Run("branch_namespace_conflict", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("branch", "foo")
+
+		out, err := runGitWt(t, binPath, repo.Root, "foo/bar")
+		if err == nil {
+			t.Fatalf("git-wt should fail when the branch namespace is taken, got: %s", out)
+		}
+		if !strings.Contains(out, `branch "foo" exists`) {
+			t.Errorf("output should mention the conflicting branch, got: %s", out)
+		}
+		if !strings.Contains(out, `"foo-bar"`) {
+			t.Errorf("output should suggest another name, got: %s", out)
+		}
+
+		if _, err := os.Stat(filepath.Join(repo.Root, ".wt")); !os.IsNotExist(err) {
+			t.Error("no worktree directory should be created for a conflicting branch name")
+		}
+	})
 }
 
 func TestE2E_SwitchWorktree(t *testing.T) {
@@ -571,6 +675,70 @@ func TestE2E_CLI(t *testing.T) {
 		// (should be a git error about invalid reference, not Cobra help)
 	})
 
+	// Subcommands do not shadow existing branches and worktrees of the same name
+	t.Run("branch_named_sync", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("branch", "sync")
+
+		out, err := runGitWt(t, binPath, repo.Root, "sync")
+		if err != nil {
+			t.Fatalf("git-wt sync failed: %v\noutput: %s", err, out)
+		}
+		if want := filepath.Join(repo.Root, ".wt", "sync"); worktreePath(out) != want {
+			t.Errorf("git wt sync should switch to the worktree for branch sync %q, got: %s", want, out)
+		}
+
+		// The worktree is switched to again instead of syncing
+		out, err = runGitWt(t, binPath, repo.Root, "sync")
+		if err != nil {
+			t.Fatalf("git-wt sync failed: %v\noutput: %s", err, out)
+		}
+		if want := filepath.Join(repo.Root, ".wt", "sync"); worktreePath(out) != want {
+			t.Errorf("git wt sync should switch to the existing worktree %q, got: %s", want, out)
+		}
+	})
+
+	t.Run("worktree_named_config", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		// Without a branch named config, the subcommand runs
+		out, err := runGitWt(t, binPath, repo.Root, "config")
+		if err != nil {
+			t.Fatalf("git-wt config failed: %v\noutput: %s", err, out)
+		}
+		if !strings.Contains(out, "wt.basedir") {
+			t.Errorf("git wt config should show the configuration, got: %s", out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--", "config")
+		if err != nil {
+			t.Fatalf("git-wt -- config failed: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		out, err = runGitWt(t, binPath, repo.Root, "config")
+		if err != nil {
+			t.Fatalf("git-wt config failed: %v\noutput: %s", err, out)
+		}
+		if worktreePath(out) != wtPath {
+			t.Errorf("git wt config should switch to the worktree %q, got: %s", wtPath, out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "config")
+		if err != nil {
+			t.Fatalf("git-wt -D config failed: %v\noutput: %s", err, out)
+		}
+		if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+			t.Errorf("worktree %s should be deleted", wtPath)
+		}
+	})
+
 	t.Run("version", func(t *testing.T) {
 		t.Parallel()
 		out, err := runGitWt(t, binPath, t.TempDir(), "--version")
@@ -0,0 +1,241 @@
+// config_cmd_test.go contains tests for the config subcommand (show, --json, --branch, --profile, root flags, set, unset) and config warnings.
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+type configEntry struct {
+	Key        string `json:"key"`
+	Value      any    `json:"value"`
+	Origin     string `json:"origin"`
+	Subsection string `json:"subsection"`
+	Expanded   string `json:"expanded"`
+	Note       string `json:"note"`
+}
+
+func TestE2E_ConfigCommand(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("json_origins", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitwt", "[wt]\n\tnocd = true\n\thook = npm install\n")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.basedir", "../{gitroot}-wt")
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "config", "--json", "--copyignored")
+		if err != nil {
+			t.Fatalf("git wt config failed: %v\nstderr: %s", err, stderr)
+		}
+		var entries []configEntry
+		if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+			t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
+		}
+		byKey := make(map[string]configEntry)
+		for _, e := range entries {
+			if e.Key == "wt.hook" && e.Origin == "file" {
+				if !strings.HasPrefix(e.Note, "not trusted") {
+					t.Errorf("hooks from .gitwt note = %q, want not trusted", e.Note)
+				}
+				continue
+			}
+			byKey[e.Key] = e
+		}
+
+		wantBaseDir := filepath.Join(filepath.Dir(repo.Root), filepath.Base(repo.Root)+"-wt")
+		if e := byKey["wt.basedir"]; e.Origin != "local" || e.Expanded != wantBaseDir {
+			t.Errorf("wt.basedir = %+v, want origin local, expanded %q", e, wantBaseDir)
+		}
+		if e := byKey["wt.copyignored"]; e.Origin != "flag" || e.Value != true {
+			t.Errorf("wt.copyignored = %+v, want true from flag", e)
+		}
+		if e := byKey["wt.nocd"]; e.Origin != "file" || e.Value != "all" {
+			t.Errorf("wt.nocd = %+v, want all from file", e)
+		}
+		if e := byKey["wt.copyuntracked"]; e.Origin != "default" {
+			t.Errorf("wt.copyuntracked origin = %q, want default", e.Origin)
+		}
+	})
+
+	t.Run("branch_and_profile", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.basedir", "../wt")
+		repo.Git("config", "wt.release/*.basedir", "../releases")
+		repo.Git("config", "wt.profile.full.copyignored", "true")
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "config", "--json", "--branch", "release/1.0", "--profile", "full")
+		if err != nil {
+			t.Fatalf("git wt config failed: %v\nstderr: %s", err, stderr)
+		}
+		var entries []configEntry
+		if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+			t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
+		}
+		byKey := make(map[string]configEntry)
+		for _, e := range entries {
+			byKey[e.Key] = e
+		}
+		if e := byKey["wt.basedir"]; e.Value != "../releases" || e.Origin != "local" || e.Subsection != "release/*" {
+			t.Errorf("wt.basedir = %+v, want ../releases from local (release/*)", e)
+		}
+		if e := byKey["wt.copyignored"]; e.Value != true || e.Subsection != "profile.full" {
+			t.Errorf("wt.copyignored = %+v, want true from profile.full", e)
+		}
+		if e := byKey["wt.defaultprofile"]; e.Note != "applied profile: full" {
+			t.Errorf("wt.defaultprofile note = %q, want applied profile: full", e.Note)
+		}
+
+		// An unknown profile is an error
+		if out, err := runGitWt(t, binPath, repo.Root, "config", "--profile", "light"); err == nil {
+			t.Errorf("git wt config --profile light should fail, output: %s", out)
+		} else if !strings.Contains(out, `unknown profile "light"`) {
+			t.Errorf("output should mention the unknown profile, got: %s", out)
+		}
+	})
+
+	t.Run("root_flags", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.hook", "npm install")
+
+		// Flags of git wt that are not persistent are accepted too
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "config", "--json", "--hook", "make setup", "--dirname", "{branch_flat}", "--no-create", "--nocd")
+		if err != nil {
+			t.Fatalf("git wt config failed: %v\nstderr: %s", err, stderr)
+		}
+		var entries []configEntry
+		if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+			t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
+		}
+		byKey := make(map[string]configEntry)
+		for _, e := range entries {
+			byKey[e.Key] = e
+		}
+		if e := byKey["wt.hook"]; e.Origin != "flag" || fmt.Sprint(e.Value) != "[make setup]" {
+			t.Errorf("wt.hook = %+v, want [make setup] from flag", e)
+		}
+		if e := byKey["wt.dirname"]; e.Origin != "flag" || e.Value != "{branch_flat}" {
+			t.Errorf("wt.dirname = %+v, want {branch_flat} from flag", e)
+		}
+		if e := byKey["wt.create"]; e.Origin != "flag" || e.Value != "never" {
+			t.Errorf("wt.create = %+v, want never from flag", e)
+		}
+		if e := byKey["wt.nocd"]; e.Origin != "flag" || e.Value != "all" {
+			t.Errorf("wt.nocd = %+v, want all from flag", e)
+		}
+		if e := byKey["wt.remote"]; e.Origin != "default" {
+			t.Errorf("wt.remote origin = %q, want default", e.Origin)
+		}
+	})
+
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "--add", "wt.copy", ".env")
+		repo.Git("config", "--add", "wt.copy", "*.local")
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "config")
+		if err != nil {
+			t.Fatalf("git wt config failed: %v\nstderr: %s", err, stderr)
+		}
+		for _, line := range strings.Split(stdout, "\n") {
+			if strings.HasPrefix(line, "wt.copy ") {
+				if !strings.Contains(line, ".env, *.local") || !strings.Contains(line, "local") {
+					t.Errorf("unexpected wt.copy row: %q", line)
+				}
+				return
+			}
+		}
+		t.Errorf("wt.copy row not found in output:\n%s", stdout)
+	})
+
+	t.Run("set_unset", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		if out, err := runGitWt(t, binPath, repo.Root, "config", "set", "wt.copy", ".env", "*.local"); err != nil {
+			t.Fatalf("git wt config set failed: %v\noutput: %s", err, out)
+		}
+		if got := repo.Git("config", "--get-all", "wt.copy"); strings.TrimSpace(got) != ".env\n*.local" {
+			t.Errorf("wt.copy = %q, want %q", got, ".env\n*.local")
+		}
+
+		for _, args := range [][]string{
+			{"config", "set", "wt.nocd", "maybe"},
+			{"config", "set", "wt.portbase", "70000"},
+			{"config", "set", "wt.basedri", "../wt"},
+			{"config", "set", "wt.basedir", "a", "b"},
+		} {
+			if out, err := runGitWt(t, binPath, repo.Root, args...); err == nil {
+				t.Errorf("git wt %s should fail\noutput: %s", strings.Join(args, " "), out)
+			}
+		}
+
+		if out, err := runGitWt(t, binPath, repo.Root, "config", "unset", "wt.copy"); err != nil {
+			t.Fatalf("git wt config unset failed: %v\noutput: %s", err, out)
+		}
+		if _, err := repo.GitE("config", "--get-all", "wt.copy"); err == nil {
+			t.Error("wt.copy should be unset")
+		}
+	})
+
+	t.Run("warnings", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitignore", ".env\n")
+		repo.Commit("initial commit")
+		repo.CreateFile(".env", "SECRET=value")
+		repo.Git("config", "wt.copyignored", "yes")
+		repo.Git("config", "wt.copyignore", "true")
+		repo.Git("config", "wt.portblock", "many")
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "warnings-test")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\nstderr: %s", err, stderr)
+		}
+		if _, err := os.Stat(filepath.Join(worktreePath(stdout), ".env")); err != nil {
+			t.Errorf("wt.copyignored=yes should copy ignored files: %v", err)
+		}
+		for _, want := range []string{
+			`warning: unknown config key "wt.copyignore" (did you mean "wt.copyignored"?)`,
+			`warning: invalid wt.portblock: bad numeric value "many" (ignored)`,
+		} {
+			if !strings.Contains(stderr, want) {
+				t.Errorf("stderr should contain %q, got: %s", want, stderr)
+			}
+			// Each warning is printed once, although config is loaded several times
+			if n := strings.Count(stderr, want); n > 1 {
+				t.Errorf("stderr should contain %q once, got %d times: %s", want, n, stderr)
+			}
+		}
+
+		// Completion does not print warnings
+		_, stderr, err = runGitWtStdout(t, binPath, repo.Root, "__complete", "")
+		if err != nil {
+			t.Fatalf("git wt __complete failed: %v\nstderr: %s", err, stderr)
+		}
+		if strings.Contains(stderr, "warning:") {
+			t.Errorf("completion should not print config warnings, got: %s", stderr)
+		}
+	})
+}
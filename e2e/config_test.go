@@ -1,8 +1,13 @@
 // config_test.go contains configuration and flag tests:
-//   - TestE2E_CopyOptions: copy options tests (copyignored config/flag, copyuntracked, copymodified, multiple flags, flag overrides)
-//   - TestE2E_Basedir: basedir tests (config, flag)
-//   - TestE2E_Nocd: nocd tests (config, config_with_init, create_config)
-//   - TestE2E_Hooks: hook tests (flag, config, multiple, not_run_on_existing, flag_overrides_config, failure, output_to_stderr)
+//   - TestE2E_CopyOptions: copy options tests (copyignored config/flag, copyuntracked, copymodified, multiple flags, flag overrides, link, hardlink, copy-from, copysource, template, worktreeinclude)
+//   - TestE2E_Basedir: basedir tests (config, flag, branch_pattern, remote_template)
+//   - TestE2E_Dirname: dirname template tests (branch_flat, short_collision, nested_collision)
+//   - TestE2E_BranchTemplate: branch name template and pattern tests (new_branch, existing_branch, pattern)
+//   - TestE2E_Create: wt.create tests (never, confirm_without_terminal, create_flag, no_create_flag)
+//   - TestE2E_StartPoint: wt.startpoint and wt.fetch tests (from_feature_worktree, fetch, option)
+//   - TestE2E_Nocd: nocd tests (config, repo_config, config_with_init, create_config, branch_pattern, profile)
+//   - TestE2E_Hooks: hook tests (flag, config, repo_config, multiple, not_run_on_existing, flag_overrides_config, failure, output_to_stderr)
+//   - TestE2E_Profile: named profile tests (flag, defaultprofile, flag_overrides_profile)
 //   - TestE2E_Complete: __complete command output tests
 package e2e
 
@@ -276,74 +281,750 @@ func TestE2E_CopyOptions(t *testing.T) {
 			t.Error(".worktrees/.gitignore should NOT have been copied (basedir should be excluded)")
 		}
 	})
+
+	t.Run("link_config", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitignore", ".env\nnode_modules/\n")
+		repo.Commit("initial commit")
+
+		// Create ignored files
+		repo.CreateFile(".env", "SECRET=link-test")
+		repo.CreateFile("node_modules/pkg/index.js", "module.exports = {}")
+
+		repo.Git("config", "wt.copyignored", "true")
+		repo.Git("config", "wt.link", "node_modules/")
+
+		out, err := runGitWt(t, binPath, repo.Root, "link-config-test")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		// node_modules should be symlinked to the source directory
+		target, err := os.Readlink(filepath.Join(wtPath, "node_modules"))
+		if err != nil {
+			t.Fatalf("node_modules should be a symlink: %v", err)
+		}
+		if target != repo.Path("node_modules") {
+			t.Errorf("node_modules symlink target = %q, want %q", target, repo.Path("node_modules"))
+		}
+
+		// .env should still be copied as a regular file
+		info, err := os.Lstat(filepath.Join(wtPath, ".env"))
+		if err != nil {
+			t.Fatalf(".env was not copied to worktree: %v", err)
+		}
+		if !info.Mode().IsRegular() {
+			t.Error(".env should be a regular file")
+		}
+	})
+
+	t.Run("copy_from_flag", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitignore", ".env\n")
+		repo.Commit("initial commit")
+
+		repo.CreateFile(".env", "SECRET=main")
+
+		// Create a feature worktree with a stale .env
+		out, err := runGitWt(t, binPath, repo.Root, "--copyignored", "stale-feature")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		featurePath := worktreePath(out)
+		if err := os.WriteFile(filepath.Join(featurePath, ".env"), []byte("SECRET=stale"), 0600); err != nil {
+			t.Fatalf("failed to write .env: %v", err)
+		}
+
+		// From inside the feature worktree, copy from the main branch worktree
+		out, err = runGitWt(t, binPath, featurePath, "--copyignored", "--copy-from", "main", "copy-from-main")
+		if err != nil {
+			t.Fatalf("failed to create worktree with --copy-from: %v\noutput: %s", err, out)
+		}
+		content, err := os.ReadFile(filepath.Join(worktreePath(out), ".env"))
+		if err != nil {
+			t.Fatalf(".env was not copied to worktree: %v", err)
+		}
+		if string(content) != "SECRET=main" {
+			t.Errorf(".env content = %q, want %q", string(content), "SECRET=main")
+		}
+
+		// Copy from the feature worktree by branch name
+		out, err = runGitWt(t, binPath, repo.Root, "--copyignored", "--copy-from", "stale-feature", "copy-from-feature")
+		if err != nil {
+			t.Fatalf("failed to create worktree with --copy-from: %v\noutput: %s", err, out)
+		}
+		content, err = os.ReadFile(filepath.Join(worktreePath(out), ".env"))
+		if err != nil {
+			t.Fatalf(".env was not copied to worktree: %v", err)
+		}
+		if string(content) != "SECRET=stale" {
+			t.Errorf(".env content = %q, want %q", string(content), "SECRET=stale")
+		}
+
+		// Unknown copy source is an error
+		out, err = runGitWt(t, binPath, repo.Root, "--copy-from", "no-such-worktree", "copy-from-unknown")
+		if err == nil {
+			t.Fatalf("expected error for unknown copy source, got output: %s", out)
+		}
+		if !strings.Contains(out, "no-such-worktree") {
+			t.Errorf("error should mention the copy source, got: %s", out)
+		}
+	})
+
+	t.Run("copy_from_config_main", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitignore", ".env\n")
+		repo.Commit("initial commit")
+
+		repo.CreateFile(".env", "SECRET=main")
+		repo.Git("config", "wt.copyignored", "true")
+
+		out, err := runGitWt(t, binPath, repo.Root, "stale-feature")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		featurePath := worktreePath(out)
+		if err := os.WriteFile(filepath.Join(featurePath, ".env"), []byte("SECRET=stale"), 0600); err != nil {
+			t.Fatalf("failed to write .env: %v", err)
+		}
+
+		repo.Git("config", "wt.copyfrom", "main")
+
+		out, err = runGitWt(t, binPath, featurePath, "copy-from-config")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		content, err := os.ReadFile(filepath.Join(worktreePath(out), ".env"))
+		if err != nil {
+			t.Fatalf(".env was not copied to worktree: %v", err)
+		}
+		if string(content) != "SECRET=main" {
+			t.Errorf(".env content = %q, want %q", string(content), "SECRET=main")
+		}
+	})
+
+	t.Run("hardlink_flag", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitignore", ".venv/\n")
+		repo.Commit("initial commit")
+
+		repo.CreateFile(".venv/lib/site.py", "print('site')")
+
+		out, err := runGitWt(t, binPath, repo.Root, "--hardlink", ".venv/", "hardlink-flag-test")
+		if err != nil {
+			t.Fatalf("failed to create worktree with --hardlink flag: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		srcInfo, err := os.Stat(repo.Path(".venv/lib/site.py"))
+		if err != nil {
+			t.Fatalf("failed to stat source file: %v", err)
+		}
+		dstInfo, err := os.Stat(filepath.Join(wtPath, ".venv/lib/site.py"))
+		if err != nil {
+			t.Fatalf(".venv/lib/site.py was not linked to worktree: %v", err)
+		}
+		if !os.SameFile(srcInfo, dstInfo) {
+			t.Error(".venv/lib/site.py should be a hardlink to the source file")
+		}
+	})
+
+	t.Run("copysource_config", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		// Secrets live outside the repository, in a directory named after it
+		secretsDir := t.TempDir()
+		repoSecrets := filepath.Join(secretsDir, filepath.Base(repo.Root))
+		if err := os.MkdirAll(repoSecrets, 0755); err != nil {
+			t.Fatalf("failed to create secrets dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(repoSecrets, ".env"), []byte("SECRET=external"), 0600); err != nil {
+			t.Fatalf("failed to write .env: %v", err)
+		}
+		repo.Git("config", "wt.copysource", filepath.Join(secretsDir, "{gitroot}"))
+
+		out, err := runGitWt(t, binPath, repo.Root, "copysource-test")
+		if err != nil {
+			t.Fatalf("failed to create worktree with wt.copysource: %v\noutput: %s", err, out)
+		}
+		info, err := os.Stat(filepath.Join(worktreePath(out), ".env"))
+		if err != nil {
+			t.Fatalf(".env was not copied from wt.copysource: %v", err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf(".env mode = %v, want 0600", info.Mode().Perm())
+		}
+	})
+
+	t.Run("worktreeinclude", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitignore", ".env\n*.local\n")
+		repo.CreateFile(".worktreeinclude", ".env\n")
+		repo.CreateFile(".gitattributes", "*.local wt-copy\n")
+		repo.Commit("initial commit")
+		repo.CreateFile(".env", "SECRET=value")
+		repo.CreateFile("settings.local", "local")
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--verbose", "worktreeinclude-test")
+		if err != nil {
+			t.Fatalf("failed to create worktree with .worktreeinclude: %v\nstderr: %s", err, stderr)
+		}
+		wtPath := worktreePath(stdout)
+		for _, name := range []string{".env", "settings.local"} {
+			if _, err := os.Stat(filepath.Join(wtPath, name)); err != nil {
+				t.Errorf("%s was not copied to worktree: %v", name, err)
+			}
+		}
+		if !strings.Contains(stderr, "from .worktreeinclude)") {
+			t.Errorf("verbose output should show .worktreeinclude as the source, got: %s", stderr)
+		}
+		if !strings.Contains(stderr, "from wt-copy attribute)") {
+			t.Errorf("verbose output should show the wt-copy attribute as the source, got: %s", stderr)
+		}
+	})
+
+	t.Run("template_config", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitignore", ".env\n")
+		repo.CreateFile(".env.wt.tmpl", "DATABASE_NAME={{.RepoName}}_{{.BranchSanitized}}\n")
+		repo.Commit("initial commit")
+		repo.Git("config", "--add", "wt.template", "*.wt.tmpl")
+
+		out, err := runGitWt(t, binPath, repo.Root, "feature/template")
+		if err != nil {
+			t.Fatalf("failed to create worktree with wt.template: %v\noutput: %s", err, out)
+		}
+		content, err := os.ReadFile(filepath.Join(worktreePath(out), ".env"))
+		if err != nil {
+			t.Fatalf(".env was not rendered to worktree: %v", err)
+		}
+		want := "DATABASE_NAME=" + filepath.Base(repo.Root) + "_feature_template\n"
+		if string(content) != want {
+			t.Errorf(".env content = %q, want %q", string(content), want)
+		}
+	})
+}
+
+func TestE2E_Basedir(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("config", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		// Set custom basedir
+		customBase := filepath.Join(repo.ParentDir(), "custom-wt-dir")
+		repo.Git("config", "wt.basedir", customBase)
+
+		// Create worktree
+		out, err := runGitWt(t, binPath, repo.Root, "custom-branch")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		// Verify worktree was created in custom location
+		expectedPath := filepath.Join(customBase, "custom-branch")
+		if wtPath != expectedPath {
+			t.Errorf("worktree path = %q, want %q", wtPath, expectedPath)
+		}
+
+		if _, err := os.Stat(wtPath); os.IsNotExist(err) {
+			t.Errorf("worktree was not created at custom path %s", wtPath)
+		}
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		// Set config basedir to one location
+		configBase := filepath.Join(repo.ParentDir(), "config-wt-dir")
+		repo.Git("config", "wt.basedir", configBase)
+
+		// Use flag to override to different location
+		flagBase := filepath.Join(repo.ParentDir(), "flag-wt-dir")
+
+		// Create worktree with --basedir flag
+		out, err := runGitWt(t, binPath, repo.Root, "--basedir", flagBase, "flag-branch")
+		if err != nil {
+			t.Fatalf("failed to create worktree with --basedir flag: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+
+		// Verify worktree was created in flag location, not config location
+		expectedPath := filepath.Join(flagBase, "flag-branch")
+		if wtPath != expectedPath {
+			t.Errorf("worktree path = %q, want %q", wtPath, expectedPath)
+		}
+
+		if _, err := os.Stat(wtPath); os.IsNotExist(err) {
+			t.Errorf("worktree was not created at flag path %s", wtPath)
+		}
+
+		// Verify it was NOT created in config location
+		configPath := filepath.Join(configBase, "flag-branch")
+		if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+			t.Errorf("worktree should not have been created at config path %s", configPath)
+		}
+	})
+
+	t.Run("remote_template", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("remote", "add", "origin", "git@github.com:k1LoW/git-wt.git")
+
+		root := filepath.Join(repo.ParentDir(), "wt")
+		repo.Git("config", "wt.basedir", root+"/{host}/{owner}/{repo}")
+
+		out, err := runGitWt(t, binPath, repo.Root, "remote-branch")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		if want := filepath.Join(root, "github.com", "k1LoW", "git-wt", "remote-branch"); worktreePath(out) != want {
+			t.Errorf("worktree path = %q, want %q", worktreePath(out), want)
+		}
+	})
+
+	t.Run("branch_pattern", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		// Release branches use their own basedir and skip the hook
+		globalBase := filepath.Join(repo.ParentDir(), "wt")
+		releaseBase := filepath.Join(repo.ParentDir(), "releases")
+		repo.Git("config", "wt.basedir", globalBase)
+		repo.Git("config", "--add", "wt.hook", "touch hook-marker.txt")
+		repo.Git("config", "wt.release/*.basedir", releaseBase)
+		repo.Git("config", "wt.release/*.hook", "")
+
+		out, err := runGitWt(t, binPath, repo.Root, "release/1.0")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		releasePath := worktreePath(out)
+		if want := filepath.Join(releaseBase, "release/1.0"); releasePath != want {
+			t.Errorf("worktree path = %q, want %q", releasePath, want)
+		}
+		if _, err := os.Stat(filepath.Join(releasePath, "hook-marker.txt")); !os.IsNotExist(err) {
+			t.Error("hook should not run for release/* branches")
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "feature-x")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		featurePath := worktreePath(out)
+		if want := filepath.Join(globalBase, "feature-x"); featurePath != want {
+			t.Errorf("worktree path = %q, want %q", featurePath, want)
+		}
+		if _, err := os.Stat(filepath.Join(featurePath, "hook-marker.txt")); os.IsNotExist(err) {
+			t.Error("hook should run for other branches")
+		}
+
+		// Switch and delete resolve the release worktree with its own basedir
+		out, err = runGitWt(t, binPath, repo.Root, "release/1.0")
+		if err != nil {
+			t.Fatalf("failed to switch worktree: %v\noutput: %s", err, out)
+		}
+		if got := worktreePath(out); got != releasePath {
+			t.Errorf("switched worktree path = %q, want %q", got, releasePath)
+		}
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "release/1.0")
+		if err != nil {
+			t.Fatalf("failed to delete worktree: %v\noutput: %s", err, out)
+		}
+		if _, err := os.Stat(releasePath); !os.IsNotExist(err) {
+			t.Errorf("worktree should be deleted: %s", releasePath)
+		}
+	})
+}
+
+func TestE2E_Dirname(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("branch_flat", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.dirname", "{branch_flat}")
+
+		out, err := runGitWt(t, binPath, repo.Root, "feature/login")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+		if want := filepath.Join(repo.Root, ".wt", "feature-login"); wtPath != want {
+			t.Errorf("worktree path = %q, want %q", wtPath, want)
+		}
+
+		// The worktree is found by both its branch and its directory name
+		for _, query := range []string{"feature/login", "feature-login"} {
+			out, err := runGitWt(t, binPath, repo.Root, query)
+			if err != nil {
+				t.Fatalf("failed to switch to %s: %v\noutput: %s", query, err, out)
+			}
+			if got := worktreePath(out); got != wtPath {
+				t.Errorf("git wt %s = %q, want %q", query, got, wtPath)
+			}
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "-D", "feature-login")
+		if err != nil {
+			t.Fatalf("failed to delete worktree: %v\noutput: %s", err, out)
+		}
+		if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+			t.Errorf("worktree should be deleted: %s", wtPath)
+		}
+	})
+
+	t.Run("short_collision", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "--dirname", "{short}", "feature/login")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		first := worktreePath(out)
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--dirname", "{short}", "bugfix/login")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\nstderr: %s", err, stderr)
+		}
+		second := strings.TrimSpace(stdout)
+		if want := first + "-2"; second != want {
+			t.Errorf("colliding worktree path = %q, want %q", second, want)
+		}
+		if !strings.Contains(stderr, "already exists") {
+			t.Errorf("stderr should report the collision, got: %s", stderr)
+		}
+	})
+
+	t.Run("nested_collision", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		// A worktree of another branch at the directory of the "review/" prefix
+		repo.Git("worktree", "add", "-b", "other", filepath.Join(repo.Root, ".wt", "review"))
+
+		out, err := runGitWt(t, binPath, repo.Root, "review/x")
+		if err == nil {
+			t.Fatalf("creating a worktree inside another worktree should fail, output: %s", out)
+		}
+		if !strings.Contains(out, "{branch_flat}") {
+			t.Errorf("error should suggest {branch_flat}, got: %s", out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--dirname", "{branch_flat}", "review/x")
+		if err != nil {
+			t.Fatalf("failed to create worktree with --dirname: %v\noutput: %s", err, out)
+		}
+		if want := filepath.Join(repo.Root, ".wt", "review-x"); worktreePath(out) != want {
+			t.Errorf("worktree path = %q, want %q", worktreePath(out), want)
+		}
+	})
+}
+
+func TestE2E_BranchTemplate(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("new_branch", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.branchtemplate", "fix/{input}")
+
+		out, err := runGitWt(t, binPath, repo.Root, "123-crash")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+		if want := filepath.Join(repo.Root, ".wt", "fix", "123-crash"); wtPath != want {
+			t.Errorf("worktree path = %q, want %q", wtPath, want)
+		}
+		if branch := repo.Git("-C", wtPath, "branch", "--show-current"); branch != "fix/123-crash" {
+			t.Errorf("branch = %q, want fix/123-crash", branch)
+		}
+
+		// Both the input and the real name resolve to the worktree
+		for _, query := range []string{"123-crash", "fix/123-crash"} {
+			out, err := runGitWt(t, binPath, repo.Root, query)
+			if err != nil {
+				t.Fatalf("failed to switch to %s: %v\noutput: %s", query, err, out)
+			}
+			if got := worktreePath(out); got != wtPath {
+				t.Errorf("git wt %s = %q, want %q", query, got, wtPath)
+			}
+		}
+	})
+
+	t.Run("existing_branch", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("branch", "legacy")
+		repo.Git("config", "wt.branchtemplate", "fix/{input}")
+		repo.Git("config", "wt.branchpattern", "^fix/")
+
+		out, err := runGitWt(t, binPath, repo.Root, "legacy")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		if want := filepath.Join(repo.Root, ".wt", "legacy"); worktreePath(out) != want {
+			t.Errorf("worktree path = %q, want %q", worktreePath(out), want)
+		}
+	})
+
+	t.Run("pattern", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.branchpattern", `^[a-z]+/[0-9]+-[a-z-]+$`)
+
+		out, err := runGitWt(t, binPath, repo.Root, "crash")
+		if err == nil {
+			t.Fatalf("creating a branch that does not match wt.branchpattern should fail, output: %s", out)
+		}
+		if !strings.Contains(out, "does not match wt.branchpattern") {
+			t.Errorf("output should mention wt.branchpattern, got: %s", out)
+		}
+		if exists := repo.Git("branch", "--list", "crash"); exists != "" {
+			t.Errorf("branch should not be created, got %q", exists)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "fix/123-crash")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+	})
 }
 
-func TestE2E_Basedir(t *testing.T) {
+func TestE2E_Create(t *testing.T) {
 	t.Parallel()
 	binPath := buildBinary(t)
 
-	t.Run("config", func(t *testing.T) {
+	t.Run("never", func(t *testing.T) {
 		t.Parallel()
 		repo := testutil.NewTestRepo(t)
 		repo.CreateFile("README.md", "# Test")
 		repo.Commit("initial commit")
+		repo.Git("branch", "feature-x")
+		repo.Git("config", "wt.create", "never")
 
-		// Set custom basedir
-		customBase := filepath.Join(repo.ParentDir(), "custom-wt-dir")
-		repo.Git("config", "wt.basedir", customBase)
+		out, err := runGitWt(t, binPath, repo.Root, "feautre-x")
+		if err == nil {
+			t.Fatalf("git-wt should fail for a branch that does not exist, got: %s", out)
+		}
+		if !strings.Contains(out, `branch "feautre-x" does not exist`) {
+			t.Errorf("output should say the branch does not exist, got: %s", out)
+		}
+		if !strings.Contains(out, "Did you mean this?\n\tfeature-x") {
+			t.Errorf("output should suggest feature-x, got: %s", out)
+		}
+		if _, err := os.Stat(filepath.Join(repo.Root, ".wt")); !os.IsNotExist(err) {
+			t.Error("no worktree directory should be created")
+		}
+		if branches := repo.Git("branch", "--list", "feautre-x"); branches != "" {
+			t.Errorf("no branch should be created, got: %s", branches)
+		}
 
-		// Create worktree
-		out, err := runGitWt(t, binPath, repo.Root, "custom-branch")
+		// Existing branches are still switched to
+		out, err = runGitWt(t, binPath, repo.Root, "feature-x")
 		if err != nil {
-			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+			t.Fatalf("failed to create worktree for existing branch: %v\noutput: %s", err, out)
 		}
-		wtPath := worktreePath(out)
+	})
 
-		// Verify worktree was created in custom location
-		expectedPath := filepath.Join(customBase, "custom-branch")
-		if wtPath != expectedPath {
-			t.Errorf("worktree path = %q, want %q", wtPath, expectedPath)
+	t.Run("confirm_without_terminal", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.create", "confirm")
+
+		out, err := runGitWt(t, binPath, repo.Root, "new-branch")
+		if err == nil {
+			t.Fatalf("git-wt should fail when it cannot ask, got: %s", out)
 		}
+		if !strings.Contains(out, "stdin is not a terminal") {
+			t.Errorf("output should explain why it cannot ask, got: %s", out)
+		}
+	})
 
-		if _, err := os.Stat(wtPath); os.IsNotExist(err) {
-			t.Errorf("worktree was not created at custom path %s", wtPath)
+	t.Run("create_flag", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.create", "never")
+
+		out, err := runGitWt(t, binPath, repo.Root, "-c", "new-branch")
+		if err != nil {
+			t.Fatalf("git-wt -c failed: %v\noutput: %s", err, out)
+		}
+		if want := filepath.Join(repo.Root, ".wt", "new-branch"); worktreePath(out) != want {
+			t.Errorf("worktree path = %q, want %q", worktreePath(out), want)
 		}
 	})
 
-	t.Run("flag", func(t *testing.T) {
+	t.Run("no_create_flag", func(t *testing.T) {
 		t.Parallel()
 		repo := testutil.NewTestRepo(t)
 		repo.CreateFile("README.md", "# Test")
 		repo.Commit("initial commit")
 
-		// Set config basedir to one location
-		configBase := filepath.Join(repo.ParentDir(), "config-wt-dir")
-		repo.Git("config", "wt.basedir", configBase)
+		out, err := runGitWt(t, binPath, repo.Root, "--no-create", "new-branch")
+		if err == nil {
+			t.Fatalf("git-wt --no-create should fail for a branch that does not exist, got: %s", out)
+		}
+		if !strings.Contains(out, "git wt --create new-branch") {
+			t.Errorf("output should tell how to create the branch, got: %s", out)
+		}
+	})
+}
 
-		// Use flag to override to different location
-		flagBase := filepath.Join(repo.ParentDir(), "flag-wt-dir")
+func TestE2E_StartPoint(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
 
-		// Create worktree with --basedir flag
-		out, err := runGitWt(t, binPath, repo.Root, "--basedir", flagBase, "flag-branch")
+	// newRepo returns a repository whose main branch is pushed to a bare repository (origin).
+	newRepo := func(t *testing.T) *testutil.TestRepo {
+		t.Helper()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		bare := filepath.Join(t.TempDir(), "remote.git")
+		repo.Git("init", "--bare", "--initial-branch=main", bare)
+		repo.Git("remote", "add", "origin", bare)
+		repo.Git("push", "-u", "origin", "main")
+		repo.Git("remote", "set-head", "origin", "--auto")
+		return repo
+	}
+
+	t.Run("from_feature_worktree", func(t *testing.T) {
+		t.Parallel()
+		repo := newRepo(t)
+		repo.Git("config", "wt.startpoint", "{remote}/{default}")
+
+		out, err := runGitWt(t, binPath, repo.Root, "feature")
 		if err != nil {
-			t.Fatalf("failed to create worktree with --basedir flag: %v\noutput: %s", err, out)
+			t.Fatalf("failed to create feature worktree: %v\noutput: %s", err, out)
 		}
-		wtPath := worktreePath(out)
+		featurePath := worktreePath(out)
+		repo.Git("-C", featurePath, "commit", "--allow-empty", "-m", "feature commit")
 
-		// Verify worktree was created in flag location, not config location
-		expectedPath := filepath.Join(flagBase, "flag-branch")
-		if wtPath != expectedPath {
-			t.Errorf("worktree path = %q, want %q", wtPath, expectedPath)
+		stdout, stderr, err := runGitWtStdout(t, binPath, featurePath, "fix")
+		if err != nil {
+			t.Fatalf("git-wt fix failed: %v\nstderr: %s", err, stderr)
+		}
+		if !strings.Contains(stderr, "Creating branch fix from origin/main (") {
+			t.Errorf("stderr should show the start point, got: %s", stderr)
+		}
+		if got, want := repo.Git("-C", stdout, "rev-parse", "HEAD"), repo.Git("rev-parse", "origin/main"); got != want {
+			t.Errorf("fix should start from origin/main (%s), got %s", want, got)
 		}
 
-		if _, err := os.Stat(wtPath); os.IsNotExist(err) {
-			t.Errorf("worktree was not created at flag path %s", wtPath)
+		// The start-point argument wins over wt.startpoint
+		_, stderr, err = runGitWtStdout(t, binPath, featurePath, "fix2", "feature")
+		if err != nil {
+			t.Fatalf("git-wt fix2 feature failed: %v\nstderr: %s", err, stderr)
+		}
+		if !strings.Contains(stderr, "Creating branch fix2 from feature (") {
+			t.Errorf("stderr should show the start-point argument, got: %s", stderr)
 		}
+	})
 
-		// Verify it was NOT created in config location
-		configPath := filepath.Join(configBase, "flag-branch")
-		if _, err := os.Stat(configPath); !os.IsNotExist(err) {
-			t.Errorf("worktree should not have been created at config path %s", configPath)
+	t.Run("fetch", func(t *testing.T) {
+		t.Parallel()
+		repo := newRepo(t)
+		old := repo.Git("rev-parse", "HEAD")
+		// Push a new commit to origin, and make origin/main stale
+		repo.Git("checkout", "-b", "other")
+		repo.CreateFile("new.txt", "new")
+		repo.Commit("new upstream commit")
+		repo.Git("push", "origin", "other:main")
+		repo.Git("checkout", "main")
+		repo.Git("update-ref", "refs/remotes/origin/main", old)
+		repo.Git("config", "wt.startpoint", "origin/main")
+
+		out, err := runGitWt(t, binPath, repo.Root, "stale")
+		if err != nil {
+			t.Fatalf("git-wt stale failed: %v\noutput: %s", err, out)
+		}
+		if _, err := os.Stat(filepath.Join(worktreePath(out), "new.txt")); !os.IsNotExist(err) {
+			t.Error("without wt.fetch, the new branch should start from the stale origin/main")
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--fetch", "fresh")
+		if err != nil {
+			t.Fatalf("git-wt --fetch fresh failed: %v\noutput: %s", err, out)
+		}
+		if _, err := os.Stat(filepath.Join(worktreePath(out), "new.txt")); err != nil {
+			t.Errorf("with --fetch, the new branch should start from the latest origin/main: %v", err)
+		}
+	})
+
+	t.Run("option", func(t *testing.T) {
+		t.Parallel()
+		repo := newRepo(t)
+		marker := filepath.Join(t.TempDir(), "pwned")
+		repo.Git("config", "wt.startpoint", "origin/--upload-pack=touch "+marker+";")
+		repo.Git("config", "wt.fetch", "true")
+
+		out, err := runGitWt(t, binPath, repo.Root, "newbranch")
+		if err == nil {
+			t.Fatalf("git-wt newbranch should fail for an invalid start point, got: %s", out)
+		}
+		if _, err := os.Stat(marker); !os.IsNotExist(err) {
+			t.Error("wt.startpoint must not be passed to git fetch as an option")
+		}
+
+		repo.Git("config", "wt.startpoint", "--upload-pack=touch "+marker+";")
+		out, err = runGitWt(t, binPath, repo.Root, "newbranch")
+		if err == nil {
+			t.Fatalf("git-wt newbranch should fail for a start point that starts with -, got: %s", out)
+		}
+		if !strings.Contains(out, "invalid start point") {
+			t.Errorf("output should mention the invalid start point, got: %s", out)
+		}
+		if _, err := os.Stat(marker); !os.IsNotExist(err) {
+			t.Error("wt.startpoint must not be passed to git as an option")
 		}
 	})
 }
@@ -395,6 +1076,43 @@ pwd
 		}
 	})
 
+	t.Run("repo_config_bash", func(t *testing.T) {
+		t.Parallel()
+		if _, err := exec.LookPath("bash"); err != nil {
+			t.Skip("bash not available")
+		}
+
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		// Set wt.nocd in the repository config file
+		repo.CreateFile(".gitwt", "[wt]\n\tnocd = Yes\n")
+		repo.Commit("initial commit")
+
+		script := fmt.Sprintf(`
+set -e
+cd %q
+export PATH="%s:$PATH"
+eval "$(git wt --init bash)"
+
+# Test: git wt <branch> with wt.nocd=yes in .gitwt should NOT cd to the worktree
+git wt nocd-repo-config-bash-test
+pwd
+`, repo.Root, filepath.Dir(binPath))
+
+		cmd := exec.Command("bash", "-c", script) //#nosec G204
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("bash shell integration with wt.nocd in .gitwt failed: %v\noutput: %s", err, out)
+		}
+
+		output := strings.TrimSpace(string(out))
+		lines := strings.Split(output, "\n")
+		pwd := lines[len(lines)-1]
+		if pwd != repo.Root {
+			t.Errorf("pwd should be original repo root %q, got: %s", repo.Root, pwd)
+		}
+	})
+
 	t.Run("config_zsh", func(t *testing.T) {
 		t.Parallel()
 		if _, err := exec.LookPath("zsh"); err != nil {
@@ -688,6 +1406,92 @@ echo "EXISTING_PWD=$EXISTING_PWD"
 			t.Errorf("EXISTING_PWD should contain worktree path when switching to existing worktree with wt.nocd=create, got: %s", existingPwd) //nostyle:errorstrings
 		}
 	})
+
+	t.Run("branch_pattern_bash", func(t *testing.T) {
+		t.Parallel()
+		if _, err := exec.LookPath("bash"); err != nil {
+			t.Skip("bash not available")
+		}
+
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		// Set wt.nocd only for scratch branches
+		repo.Git("config", "wt.scratch/*.nocd", "true")
+
+		script := fmt.Sprintf(`
+set -e
+cd %q
+export PATH="%s:$PATH"
+eval "$(git wt --init bash)"
+
+# Should NOT cd because wt.scratch/*.nocd=true
+git wt scratch/nocd
+echo "SCRATCH_PWD=$(pwd)"
+
+# Should cd because the pattern does not match
+git wt feature-nocd
+echo "FEATURE_PWD=$(pwd)"
+`, repo.Root, filepath.Dir(binPath))
+
+		cmd := exec.Command("bash", "-c", script) //#nosec G204
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("bash shell integration with wt.<pattern>.nocd failed: %v\noutput: %s", err, out)
+		}
+
+		output := string(out)
+		if !strings.Contains(output, "SCRATCH_PWD="+repo.Root+"\n") {
+			t.Errorf("should not cd to a worktree for a branch matching the nocd pattern, got: %s", output) //nostyle:errorstrings
+		}
+		if !strings.Contains(output, "/feature-nocd\n") {
+			t.Errorf("should cd to a worktree for a branch not matching the nocd pattern, got: %s", output) //nostyle:errorstrings
+		}
+	})
+
+	t.Run("profile_bash", func(t *testing.T) {
+		t.Parallel()
+		if _, err := exec.LookPath("bash"); err != nil {
+			t.Skip("bash not available")
+		}
+
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		// Set wt.nocd only in the background profile
+		repo.Git("config", "wt.profile.background.nocd", "true")
+
+		script := fmt.Sprintf(`
+set -e
+cd %q
+export PATH="%s:$PATH"
+eval "$(git wt --init bash)"
+
+# Should NOT cd because the background profile sets wt.nocd
+git wt --profile background profile-nocd
+echo "PROFILE_PWD=$(pwd)"
+
+# Should cd without the profile
+git wt profile-nocd
+echo "DEFAULT_PWD=$(pwd)"
+`, repo.Root, filepath.Dir(binPath))
+
+		cmd := exec.Command("bash", "-c", script) //#nosec G204
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("bash shell integration with wt.profile.<name>.nocd failed: %v\noutput: %s", err, out)
+		}
+
+		output := string(out)
+		if !strings.Contains(output, "PROFILE_PWD="+repo.Root+"\n") {
+			t.Errorf("should not cd to the worktree with a profile that sets nocd, got: %s", output) //nostyle:errorstrings
+		}
+		if !strings.Contains(output, "/profile-nocd\n") {
+			t.Errorf("should cd to the worktree without the profile, got: %s", output) //nostyle:errorstrings
+		}
+	})
 }
 
 func TestE2E_Hooks(t *testing.T) {
@@ -737,6 +1541,37 @@ func TestE2E_Hooks(t *testing.T) {
 		}
 	})
 
+	t.Run("repo_config", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitwt", "[wt]\n\thook = touch repo-hook-marker.txt\n")
+		repo.Commit("initial commit")
+
+		// Hooks from .gitwt are skipped until they are trusted
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "untrusted-hook-test")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\nstderr: %s", err, stderr)
+		}
+		if _, err := os.Stat(filepath.Join(worktreePath(stdout), "repo-hook-marker.txt")); !os.IsNotExist(err) {
+			t.Error("untrusted hook from .gitwt should not run")
+		}
+		if !strings.Contains(stderr, "git config wt.trustedhooks ") {
+			t.Errorf("stderr should explain how to trust the hooks, got: %s", stderr)
+		}
+
+		hash := strings.TrimSpace(stderr[strings.Index(stderr, "wt.trustedhooks ")+len("wt.trustedhooks "):])
+		repo.Git("config", "wt.trustedhooks", hash)
+
+		out, err := runGitWt(t, binPath, repo.Root, "trusted-hook-test")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		if _, err := os.Stat(filepath.Join(worktreePath(out), "repo-hook-marker.txt")); err != nil {
+			t.Errorf("trusted hook from .gitwt did not run: %v", err)
+		}
+	})
+
 	t.Run("multiple", func(t *testing.T) {
 		t.Parallel()
 		repo := testutil.NewTestRepo(t)
@@ -876,6 +1711,81 @@ func TestE2E_Hooks(t *testing.T) {
 	})
 }
 
+func TestE2E_Profile(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	setup := func(t *testing.T) *testutil.TestRepo {
+		t.Helper()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitignore", ".env\n")
+		repo.Commit("initial commit")
+		repo.CreateFile(".env", "SECRET=supersecret")
+
+		repo.Git("config", "wt.profile.full.copyignored", "true")
+		repo.Git("config", "wt.profile.full.hook", "touch hook-marker.txt")
+		repo.Git("config", "wt.profile.light.hook", "")
+		repo.Git("config", "--add", "wt.hook", "touch hook-marker.txt")
+		return repo
+	}
+
+	exists := func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	t.Run("flag", func(t *testing.T) {
+		t.Parallel()
+		repo := setup(t)
+
+		out, err := runGitWt(t, binPath, repo.Root, "--profile", "full", "full-branch")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath := worktreePath(out)
+		if !exists(filepath.Join(wtPath, ".env")) || !exists(filepath.Join(wtPath, "hook-marker.txt")) {
+			t.Error("full profile should copy .env and run the hook")
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "--profile", "light", "light-branch")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		wtPath = worktreePath(out)
+		if exists(filepath.Join(wtPath, ".env")) || exists(filepath.Join(wtPath, "hook-marker.txt")) {
+			t.Error("light profile should not copy .env nor run hooks")
+		}
+	})
+
+	t.Run("defaultprofile", func(t *testing.T) {
+		t.Parallel()
+		repo := setup(t)
+		repo.Git("config", "wt.defaultprofile", "light")
+
+		out, err := runGitWt(t, binPath, repo.Root, "default-branch")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		if exists(filepath.Join(worktreePath(out), "hook-marker.txt")) {
+			t.Error("wt.defaultprofile light should disable hooks")
+		}
+	})
+
+	t.Run("flag_overrides_profile", func(t *testing.T) {
+		t.Parallel()
+		repo := setup(t)
+
+		out, err := runGitWt(t, binPath, repo.Root, "--profile", "light", "--hook", "touch flag-marker.txt", "flag-branch")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		if !exists(filepath.Join(worktreePath(out), "flag-marker.txt")) {
+			t.Error("--hook should override the profile's hooks")
+		}
+	})
+}
+
 func TestE2E_Relative(t *testing.T) {
 	t.Parallel()
 	binPath := buildBinary(t)
@@ -0,0 +1,159 @@
+// ports_test.go contains tests for per-worktree port allocation and the ports subcommand.
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestE2E_Ports(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	t.Run("hooks_and_templates", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".env.wt.tmpl", "PORT={{index .Ports 0}}\n")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.portblock", "2")
+		repo.Git("config", "wt.portbase", "41000")
+		repo.Git("config", "wt.template", "*.wt.tmpl")
+
+		out, err := runGitWt(t, binPath, repo.Root, "--hook", "echo $GIT_WT_PORTS > ports.txt", "first")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		first := worktreePath(out)
+		content, err := os.ReadFile(filepath.Join(first, "ports.txt"))
+		if err != nil {
+			t.Fatalf("hook did not run: %v", err)
+		}
+		if strings.TrimSpace(string(content)) != "41000,41001" {
+			t.Errorf("GIT_WT_PORTS = %q, want %q", strings.TrimSpace(string(content)), "41000,41001")
+		}
+		content, err = os.ReadFile(filepath.Join(first, ".env"))
+		if err != nil {
+			t.Fatalf(".env was not rendered: %v", err)
+		}
+		if string(content) != "PORT=41000\n" {
+			t.Errorf(".env content = %q, want %q", string(content), "PORT=41000\n")
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "second")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+
+		stdout, _, err := runGitWtStdout(t, binPath, repo.Root, "ports", "second")
+		if err != nil {
+			t.Fatalf("ports failed: %v", err)
+		}
+		if stdout != "41002\n41003" {
+			t.Errorf("ports second = %q, want %q", stdout, "41002\n41003")
+		}
+
+		stdout, _, err = runGitWtStdout(t, binPath, repo.Root, "ports")
+		if err != nil {
+			t.Fatalf("ports failed: %v", err)
+		}
+		if !strings.Contains(stdout, "41000,41001") || !strings.Contains(stdout, "41002,41003") {
+			t.Errorf("ports output should list both blocks, got: %s", stdout)
+		}
+	})
+
+	t.Run("delete_frees_ports", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.portblock", "3")
+		repo.Git("config", "wt.portbase", "42000")
+
+		if out, err := runGitWt(t, binPath, repo.Root, "to-delete"); err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		if out, err := runGitWt(t, binPath, repo.Root, "-D", "to-delete"); err != nil {
+			t.Fatalf("failed to delete worktree: %v\noutput: %s", err, out)
+		}
+
+		// The freed block is reused
+		out, err := runGitWt(t, binPath, repo.Root, "reuse")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		stdout, _, err := runGitWtStdout(t, binPath, repo.Root, "ports", "reuse")
+		if err != nil {
+			t.Fatalf("ports failed: %v", err)
+		}
+		if !strings.HasPrefix(stdout, "42000\n") {
+			t.Errorf("ports reuse = %q, want block starting at 42000", stdout)
+		}
+	})
+
+	t.Run("symlinked_basedir", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		// Worktrees are created through a symlink (e.g., /tmp -> /private/tmp on macOS)
+		realDir := filepath.Join(repo.ParentDir(), "real")
+		if err := os.MkdirAll(realDir, 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		linkDir := filepath.Join(repo.ParentDir(), "link")
+		if err := os.Symlink(realDir, linkDir); err != nil {
+			t.Fatalf("failed to create symlink: %v", err)
+		}
+		repo.Git("config", "wt.basedir", linkDir)
+		repo.Git("config", "wt.portblock", "1")
+		repo.Git("config", "wt.portbase", "43000")
+
+		if out, err := runGitWt(t, binPath, repo.Root, "linked"); err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		stdout, _, err := runGitWtStdout(t, binPath, repo.Root, "ports")
+		if err != nil {
+			t.Fatalf("ports failed: %v", err)
+		}
+		var branch string
+		for _, line := range strings.Split(stdout, "\n") {
+			if fields := strings.Fields(line); len(fields) == 3 && fields[2] == "43000" {
+				branch = fields[1]
+			}
+		}
+		if branch != "linked" {
+			t.Errorf("ports output should show the branch of the worktree, got: %s", stdout)
+		}
+	})
+
+	t.Run("no_ports_allocated", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		if out, err := runGitWt(t, binPath, repo.Root, "no-ports"); err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		out, err := runGitWt(t, binPath, repo.Root, "ports", "no-ports")
+		if err == nil {
+			t.Errorf("ports should fail without allocated ports, output: %s", out)
+		}
+
+		// Deleting a worktree does not create the port registry
+		if out, err := runGitWt(t, binPath, repo.Root, "-D", "no-ports"); err != nil {
+			t.Fatalf("failed to delete worktree: %v\noutput: %s", err, out)
+		}
+		for _, name := range []string{"wt-ports.json", "wt-ports.lock"} {
+			if _, err := os.Stat(filepath.Join(repo.Root, ".git", name)); !os.IsNotExist(err) {
+				t.Errorf("%s should not be created without wt.portblock", name)
+			}
+		}
+	})
+}
@@ -0,0 +1,242 @@
+// pr_test.go contains tests for checking out pull/merge requests with --pr and the pr/<number> target.
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestE2E_PullRequest(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	// newRepo returns a repository whose origin is a bare repository carrying a contribution
+	// as refs/pull/7/head and refs/merge-requests/7/head.
+	newRepo := func(t *testing.T) *testutil.TestRepo {
+		t.Helper()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		bare := filepath.Join(t.TempDir(), "remote.git")
+		repo.Git("init", "--bare", "--initial-branch=main", bare)
+		repo.Git("remote", "add", "origin", bare)
+		repo.Git("push", "origin", "main")
+
+		repo.Git("checkout", "-b", "contribution")
+		repo.CreateFile("contribution.txt", "contribution")
+		repo.Commit("contribution")
+		repo.Git("push", "origin", "HEAD:refs/pull/7/head", "HEAD:refs/merge-requests/7/head")
+		repo.Git("checkout", "main")
+		repo.Git("branch", "-D", "contribution")
+		return repo
+	}
+
+	t.Run("github", func(t *testing.T) {
+		t.Parallel()
+		repo := newRepo(t)
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--pr", "7")
+		if err != nil {
+			t.Fatalf("git-wt --pr 7 failed: %v\nstderr: %s", err, stderr)
+		}
+		if want := filepath.Join(repo.Root, ".wt", "pr", "7"); stdout != want {
+			t.Errorf("worktree path = %q, want %q", stdout, want)
+		}
+		if !strings.Contains(stderr, "Fetching pull request #7 from origin into pr/7") {
+			t.Errorf("stderr should show the fetched pull request, got: %s", stderr)
+		}
+		if _, err := os.Stat(filepath.Join(stdout, "contribution.txt")); err != nil {
+			t.Errorf("worktree should contain the pull request: %v", err)
+		}
+
+		// The pr/<number> target switches to the same worktree
+		out, err := runGitWt(t, binPath, repo.Root, "pr/7")
+		if err != nil {
+			t.Fatalf("git-wt pr/7 failed: %v\noutput: %s", err, out)
+		}
+		if out != stdout {
+			t.Errorf("git wt pr/7 = %q, want %q", out, stdout)
+		}
+	})
+
+	t.Run("gitlab_target", func(t *testing.T) {
+		t.Parallel()
+		repo := newRepo(t)
+		repo.Git("config", "wt.forge", "gitlab")
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "pr/7")
+		if err != nil {
+			t.Fatalf("git-wt pr/7 failed: %v\nstderr: %s", err, stderr)
+		}
+		if !strings.Contains(stderr, "Fetching merge request !7 from origin into pr/7") {
+			t.Errorf("stderr should show the fetched merge request, got: %s", stderr)
+		}
+		if _, err := os.Stat(filepath.Join(stdout, "contribution.txt")); err != nil {
+			t.Errorf("worktree should contain the merge request: %v", err)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+		repo := newRepo(t)
+
+		out, err := runGitWt(t, binPath, repo.Root, "--pr", "8")
+		if err == nil {
+			t.Fatalf("git-wt --pr 8 should fail for a pull request that does not exist, got: %s", out)
+		}
+		if !strings.Contains(out, "failed to fetch pull request #8 (refs/pull/8/head) from origin") {
+			t.Errorf("output should explain the failed fetch, got: %s", out)
+		}
+		if _, err := os.Stat(filepath.Join(repo.Root, ".wt")); !os.IsNotExist(err) {
+			t.Error("no worktree directory should be created")
+		}
+	})
+
+	t.Run("existing_remote_branch", func(t *testing.T) {
+		t.Parallel()
+		repo := newRepo(t)
+		// A regular branch named pr/7 on the remote wins over pull request #7
+		repo.Git("checkout", "-b", "pr/7")
+		repo.CreateFile("branch.txt", "branch")
+		repo.Commit("branch")
+		repo.Git("push", "origin", "pr/7")
+		repo.Git("checkout", "main")
+		repo.Git("branch", "-D", "pr/7")
+		repo.Git("fetch", "origin")
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "pr/7")
+		if err != nil {
+			t.Fatalf("git-wt pr/7 failed: %v\nstderr: %s", err, stderr)
+		}
+		if strings.Contains(stderr, "Fetching pull request") {
+			t.Errorf("the existing remote branch should not be overwritten by the pull request, got: %s", stderr)
+		}
+		if _, err := os.Stat(filepath.Join(stdout, "branch.txt")); err != nil {
+			t.Errorf("worktree should contain the remote branch: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(stdout, "contribution.txt")); !os.IsNotExist(err) {
+			t.Error("worktree should not contain the pull request")
+		}
+		if upstream := repo.Git("rev-parse", "--abbrev-ref", "pr/7@{upstream}"); upstream != "origin/pr/7" {
+			t.Errorf("pr/7 upstream = %q, want origin/pr/7", upstream)
+		}
+	})
+
+	t.Run("refetch", func(t *testing.T) {
+		t.Parallel()
+		repo := newRepo(t)
+
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--pr", "7")
+		if err != nil {
+			t.Fatalf("git-wt --pr 7 failed: %v\nstderr: %s", err, stderr)
+		}
+		repo.Git("worktree", "remove", stdout)
+
+		// Update the pull request
+		repo.Git("checkout", "-b", "update", "pr/7")
+		repo.CreateFile("update.txt", "update")
+		repo.Commit("update")
+		repo.Git("push", "origin", "HEAD:refs/pull/7/head")
+		repo.Git("checkout", "main")
+		repo.Git("branch", "-D", "update")
+
+		// The pr/<number> target uses the existing branch as is
+		out, err := runGitWt(t, binPath, repo.Root, "pr/7")
+		if err != nil {
+			t.Fatalf("git-wt pr/7 failed: %v\noutput: %s", err, out)
+		}
+		if _, err := os.Stat(filepath.Join(stdout, "update.txt")); !os.IsNotExist(err) {
+			t.Error("git wt pr/7 should not fetch the pull request into the existing branch")
+		}
+		repo.Git("worktree", "remove", stdout)
+
+		// --pr fetches the pull request again
+		stdout, stderr, err = runGitWtStdout(t, binPath, repo.Root, "--pr", "7")
+		if err != nil {
+			t.Fatalf("git-wt --pr 7 failed: %v\nstderr: %s", err, stderr)
+		}
+		if !strings.Contains(stderr, "Fetching pull request #7 from origin into pr/7") {
+			t.Errorf("stderr should show the fetched pull request, got: %s", stderr)
+		}
+		if _, err := os.Stat(filepath.Join(stdout, "update.txt")); err != nil {
+			t.Errorf("worktree should contain the updated pull request: %v", err)
+		}
+	})
+
+	t.Run("branch_pattern", func(t *testing.T) {
+		t.Parallel()
+		repo := newRepo(t)
+		repo.Git("config", "wt.branchpattern", "^feature/")
+
+		out, err := runGitWt(t, binPath, repo.Root, "pr/7")
+		if err == nil {
+			t.Fatalf("git-wt pr/7 should fail when pr/7 does not match wt.branchpattern, got: %s", out)
+		}
+		if strings.Contains(out, "Fetching pull request") {
+			t.Errorf("the pull request should not be fetched, got: %s", out)
+		}
+		if branches := repo.Git("branch", "--list", "pr/7"); branches != "" {
+			t.Errorf("branch pr/7 should not be created, got: %s", branches)
+		}
+
+		// --pr asks for the branch explicitly
+		if out, err := runGitWt(t, binPath, repo.Root, "--pr", "7"); err != nil {
+			t.Errorf("git-wt --pr 7 should ignore wt.branchpattern: %v\noutput: %s", err, out)
+		}
+	})
+
+	t.Run("create_never", func(t *testing.T) {
+		t.Parallel()
+		repo := newRepo(t)
+		repo.Git("config", "wt.create", "never")
+
+		out, err := runGitWt(t, binPath, repo.Root, "pr/7")
+		if err == nil {
+			t.Fatalf("git-wt pr/7 should fail with wt.create=never, got: %s", out)
+		}
+		if branches := repo.Git("branch", "--list", "pr/7"); branches != "" {
+			t.Errorf("branch pr/7 should not be created, got: %s", branches)
+		}
+
+		// --pr asks for the branch explicitly
+		stdout, stderr, err := runGitWtStdout(t, binPath, repo.Root, "--pr", "7")
+		if err != nil {
+			t.Fatalf("git-wt --pr 7 should ignore wt.create: %v\nstderr: %s", err, stderr)
+		}
+		if _, err := os.Stat(filepath.Join(stdout, "contribution.txt")); err != nil {
+			t.Errorf("worktree should contain the pull request: %v", err)
+		}
+	})
+
+	t.Run("hostile_remote", func(t *testing.T) {
+		t.Parallel()
+		repo := newRepo(t)
+		marker := filepath.Join(t.TempDir(), "pwned")
+		repo.Git("config", "wt.remote", "--upload-pack=touch "+marker+";")
+
+		out, err := runGitWt(t, binPath, repo.Root, "pr/7")
+		if err == nil {
+			t.Fatalf("git-wt pr/7 should fail for an unknown remote, got: %s", out)
+		}
+		if !strings.Contains(out, "unknown remote") {
+			t.Errorf("output should mention the unknown remote, got: %s", out)
+		}
+		if _, err := os.Stat(marker); !os.IsNotExist(err) {
+			t.Error("wt.remote must not be passed to git fetch as an option")
+		}
+	})
+
+	t.Run("with_arguments", func(t *testing.T) {
+		t.Parallel()
+		repo := newRepo(t)
+
+		out, err := runGitWt(t, binPath, repo.Root, "--pr", "7", "feature")
+		if err == nil {
+			t.Fatalf("git-wt --pr with a branch argument should fail, got: %s", out)
+		}
+	})
+}
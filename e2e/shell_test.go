@@ -1,10 +1,11 @@
 // shell_test.go contains shell integration tests:
 //   - TestE2E_InitScript: --init script generation (bash/zsh/fish/powershell, nocd, unsupported_shell)
-//   - TestE2E_ShellIntegration_StdoutFormat: stdout format for shell integration compatibility
-//   - TestE2E_ShellIntegration: shell integration cd tests (bash, zsh, fish, powershell, nocd)
+//   - TestE2E_ShellIntegration_StdoutFormat: stdout format for shell integration compatibility (including the nocd marker)
+//   - TestE2E_ShellIntegration: shell integration cd tests (bash, zsh, fish, powershell, nocd, repo_config_nocd_bash)
 package e2e
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -226,6 +227,57 @@ func TestE2E_ShellIntegration_StdoutFormat(t *testing.T) {
 			t.Errorf("delete output should not be a valid directory, got: %s", stdout)
 		}
 	})
+
+	t.Run("nocd_stdout_ends_with_marker", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.nocd", "create")
+
+		runWithShellIntegration := func(args ...string) []string {
+			t.Helper()
+			cmd := exec.Command(binPath, args...)
+			cmd.Dir = repo.Root
+			cmd.Env = append(os.Environ(), "GIT_WT_SHELL_INTEGRATION=1")
+			var stdoutBuf bytes.Buffer
+			cmd.Stdout = &stdoutBuf
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("git-wt %v failed: %v", args, err)
+			}
+			return strings.Split(strings.TrimSpace(stdoutBuf.String()), "\n")
+		}
+
+		// New worktree with wt.nocd=create: path followed by the marker
+		lines := runWithShellIntegration("nocd-marker")
+		if len(lines) != 2 || lines[1] != "git-wt:nocd" {
+			t.Fatalf("stdout should be the path and the nocd marker, got: %q", lines)
+		}
+		if info, err := os.Stat(lines[0]); err != nil || !info.IsDir() {
+			t.Errorf("first line should be the worktree directory, got: %s", lines[0])
+		}
+
+		// Existing worktree with wt.nocd=create: path only
+		lines = runWithShellIntegration("nocd-marker")
+		if len(lines) != 1 {
+			t.Errorf("stdout should be the path only for an existing worktree, got: %q", lines)
+		}
+
+		// --nocd: the marker for an existing worktree too
+		lines = runWithShellIntegration("--nocd", "nocd-marker")
+		if len(lines) != 2 || lines[1] != "git-wt:nocd" {
+			t.Errorf("stdout should end with the nocd marker with --nocd, got: %q", lines)
+		}
+
+		// Without shell integration: path only
+		stdout, _, err := runGitWtStdout(t, binPath, repo.Root, "--nocd", "nocd-marker")
+		if err != nil {
+			t.Fatalf("git-wt --nocd failed: %v", err)
+		}
+		if strings.Contains(stdout, "git-wt:nocd") {
+			t.Errorf("stdout should not contain the nocd marker without shell integration, got: %s", stdout)
+		}
+	})
 }
 
 // TestE2E_ShellIntegration tests the actual shell integration with various shells.
@@ -513,4 +565,63 @@ pwd
 			t.Errorf("pwd should be original repo root %q, got: %s", repo.Root, pwd)
 		}
 	})
+
+	t.Run("repo_config_nocd_bash", func(t *testing.T) {
+		t.Parallel()
+		if _, err := exec.LookPath("bash"); err != nil {
+			t.Skip("bash not available")
+		}
+
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitwt", "[wt]\n\tnocd = true\n")
+		repo.Commit("initial commit")
+
+		// Count the invocations of git-wt with a wrapper in front of the binary
+		shimDir := t.TempDir()
+		countFile := filepath.Join(shimDir, "count")
+		shim := fmt.Sprintf("#!/bin/sh\necho >> %q\nexec %q \"$@\"\n", countFile, binPath)
+		if err := os.WriteFile(filepath.Join(shimDir, "git-wt"), []byte(shim), 0755); err != nil { //#nosec G306
+			t.Fatalf("failed to write git-wt wrapper: %v", err)
+		}
+
+		script := fmt.Sprintf(`
+set -e
+cd %q
+export PATH="%s:$PATH"
+eval "$(command %q --init bash)"
+
+# Test: git wt <branch> with wt.nocd=true in .gitwt should NOT cd to the worktree
+git wt repo-config-nocd-bash-test
+pwd
+`, repo.Root, shimDir, binPath)
+
+		cmd := exec.Command("bash", "-c", script) //#nosec G204
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("bash shell integration with wt.nocd in .gitwt failed: %v\noutput: %s", err, out)
+		}
+
+		output := strings.TrimSpace(string(out))
+		lines := strings.Split(output, "\n")
+		pwd := lines[len(lines)-1]
+		if pwd != repo.Root {
+			t.Errorf("pwd should be original repo root %q, got: %s", repo.Root, pwd)
+		}
+		if !strings.Contains(output, "repo-config-nocd-bash-test") {
+			t.Errorf("output should contain the worktree path, got: %s", output)
+		}
+		if strings.Contains(output, "git-wt:nocd") {
+			t.Errorf("output should not contain the nocd marker, got: %s", output)
+		}
+
+		// The shell integration runs git-wt once per git wt
+		count, err := os.ReadFile(countFile)
+		if err != nil {
+			t.Fatalf("failed to read invocation count: %v", err)
+		}
+		if n := strings.Count(string(count), "\n"); n != 1 {
+			t.Errorf("git-wt should run once, ran %d times", n)
+		}
+	})
 }
@@ -0,0 +1,156 @@
+// sync_test.go contains tests for the sync subcommand.
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestE2E_Sync(t *testing.T) {
+	t.Parallel()
+	binPath := buildBinary(t)
+
+	setup := func(t *testing.T) (*testutil.TestRepo, string) {
+		t.Helper()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.CreateFile(".gitignore", ".env\n")
+		repo.Commit("initial commit")
+		repo.Git("config", "wt.copyignored", "true")
+
+		repo.CreateFile(".env", "SECRET=old")
+		out, err := runGitWt(t, binPath, repo.Root, "feature")
+		if err != nil {
+			t.Fatalf("failed to create worktree: %v\noutput: %s", err, out)
+		}
+		return repo, worktreePath(out)
+	}
+
+	t.Run("check_and_sync_drifted", func(t *testing.T) {
+		t.Parallel()
+		repo, wtPath := setup(t)
+
+		// Rotate the secret in the main worktree
+		repo.CreateFile(".env", "SECRET=new")
+
+		out, err := runGitWt(t, binPath, repo.Root, "sync", "--all", "--check")
+		if err == nil {
+			t.Fatalf("sync --check should fail when files drifted, output: %s", out)
+		}
+		if !strings.Contains(out, "feature: drifted .env") {
+			t.Errorf("output should report drifted .env, got: %s", out)
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "sync", "feature")
+		if err != nil {
+			t.Fatalf("sync failed: %v\noutput: %s", err, out)
+		}
+		content, err := os.ReadFile(filepath.Join(wtPath, ".env"))
+		if err != nil {
+			t.Fatalf("failed to read .env: %v", err)
+		}
+		if string(content) != "SECRET=new" {
+			t.Errorf(".env content = %q, want %q", string(content), "SECRET=new")
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "sync", "--all", "--check")
+		if err != nil {
+			t.Errorf("sync --check should succeed after sync: %v\noutput: %s", err, out)
+		}
+	})
+
+	t.Run("modified_requires_force", func(t *testing.T) {
+		t.Parallel()
+		repo, wtPath := setup(t)
+
+		// Edit the copy in the feature worktree
+		envPath := filepath.Join(wtPath, ".env")
+		if err := os.WriteFile(envPath, []byte("SECRET=mine"), 0600); err != nil {
+			t.Fatalf("failed to write .env: %v", err)
+		}
+
+		out, err := runGitWt(t, binPath, repo.Root, "sync", "--all")
+		if err == nil {
+			t.Fatalf("sync should fail when modified files are skipped, output: %s", out)
+		}
+		if !strings.Contains(out, "--force") {
+			t.Errorf("output should suggest --force, got: %s", out)
+		}
+		content, err := os.ReadFile(envPath)
+		if err != nil {
+			t.Fatalf("failed to read .env: %v", err)
+		}
+		if string(content) != "SECRET=mine" {
+			t.Errorf(".env should not be overwritten without --force, got %q", string(content))
+		}
+
+		out, err = runGitWt(t, binPath, repo.Root, "sync", "--all", "--force")
+		if err != nil {
+			t.Fatalf("sync --force failed: %v\noutput: %s", err, out)
+		}
+		content, err = os.ReadFile(envPath)
+		if err != nil {
+			t.Fatalf("failed to read .env: %v", err)
+		}
+		if string(content) != "SECRET=old" {
+			t.Errorf(".env content = %q, want %q", string(content), "SECRET=old")
+		}
+	})
+
+	t.Run("current_worktree_from_main", func(t *testing.T) {
+		t.Parallel()
+		repo, wtPath := setup(t)
+
+		if err := os.Remove(filepath.Join(wtPath, ".env")); err != nil {
+			t.Fatalf("failed to remove .env: %v", err)
+		}
+
+		// Without arguments, the current worktree is synced from the main worktree
+		out, err := runGitWt(t, binPath, wtPath, "sync")
+		if err != nil {
+			t.Fatalf("sync failed: %v\noutput: %s", err, out)
+		}
+		if !strings.Contains(out, "missing .env (synced)") {
+			t.Errorf("output should report synced .env, got: %s", out)
+		}
+		if _, err := os.Stat(filepath.Join(wtPath, ".env")); err != nil {
+			t.Errorf(".env was not restored: %v", err)
+		}
+
+		if err := os.Remove(filepath.Join(wtPath, ".env")); err != nil {
+			t.Fatalf("failed to remove .env: %v", err)
+		}
+		out, err = runGitWt(t, binPath, wtPath, "sync", "--copy-from", "main")
+		if err != nil {
+			t.Fatalf("sync --copy-from main failed: %v\noutput: %s", err, out)
+		}
+		if _, err := os.Stat(filepath.Join(wtPath, ".env")); err != nil {
+			t.Errorf(".env was not restored: %v", err)
+		}
+
+		// Syncing the main worktree, which is the copy source, is an error
+		out, err = runGitWt(t, binPath, repo.Root, "sync")
+		if err == nil {
+			t.Errorf("sync into the copy source should fail, output: %s", out)
+		}
+	})
+
+	t.Run("branch_named_sync", func(t *testing.T) {
+		t.Parallel()
+		repo := testutil.NewTestRepo(t)
+		repo.CreateFile("README.md", "# Test")
+		repo.Commit("initial commit")
+
+		out, err := runGitWt(t, binPath, repo.Root, "--", "sync")
+		if err != nil {
+			t.Fatalf("failed to create worktree for branch sync: %v\noutput: %s", err, out)
+		}
+		if filepath.Base(worktreePath(out)) != "sync" {
+			t.Errorf("worktree path = %q, want a path ending with sync", worktreePath(out))
+		}
+	})
+}
@@ -2,33 +2,67 @@ package git
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"slices"
 	"strings"
 )
 
 const gitDefaultBranch = "master"
 
-// BranchExists checks if a branch exists (local or remote).
-func BranchExists(ctx context.Context, name string) (bool, error) {
-	// Check local branch
-	cmd, err := gitCommand(ctx, "show-ref", "--verify", "--quiet", "refs/heads/"+name)
+// BranchExists checks if a branch exists locally or on a remote.
+// remote (wt.remote) is preferred when several remotes have the branch (see FindRemoteBranch).
+func BranchExists(ctx context.Context, name, remote string) (bool, error) {
+	exists, err := LocalBranchExists(ctx, name)
+	if err != nil || exists {
+		return exists, err
+	}
+	remoteBranch, err := FindRemoteBranch(ctx, name, remote)
 	if err != nil {
 		return false, err
 	}
-	if err := cmd.Run(); err == nil {
-		return true, nil
-	}
+	return remoteBranch != nil, nil
+}
 
-	// Check remote branch (origin)
-	cmd, err = gitCommand(ctx, "show-ref", "--verify", "--quiet", "refs/remotes/origin/"+name)
+// RemoteBranch is a branch on a remote.
+type RemoteBranch struct {
+	Remote string // e.g., upstream
+	Branch string // e.g., feature
+}
+
+// String returns the name of the remote-tracking branch (e.g., upstream/feature).
+func (b RemoteBranch) String() string {
+	return b.Remote + "/" + b.Branch
+}
+
+// FindRemoteBranch looks for branch on all remotes and returns nil if no remote has it.
+// If several remotes have it, preferred (wt.remote) wins; otherwise the remote to track is ambiguous,
+// which is an error.
+func FindRemoteBranch(ctx context.Context, branch, preferred string) (*RemoteBranch, error) {
+	remotes, err := ListRemotes(ctx)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	if err := cmd.Run(); err == nil {
-		return true, nil
+	var found []string
+	for _, remote := range remotes {
+		cmd, err := gitCommand(ctx, "show-ref", "--verify", "--quiet", "refs/remotes/"+remote+"/"+branch)
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Run(); err == nil {
+			found = append(found, remote)
+		}
 	}
-
-	return false, nil
+	switch {
+	case len(found) == 0:
+		return nil, nil
+	case len(found) == 1:
+		return &RemoteBranch{Remote: found[0], Branch: branch}, nil
+	case slices.Contains(found, preferred):
+		return &RemoteBranch{Remote: preferred, Branch: branch}, nil
+	}
+	return nil, fmt.Errorf("branch %q exists on several remotes (%s); set %s to the remote to track (e.g., git config %s %s)",
+		branch, strings.Join(found, ", "), configKeyRemote, configKeyRemote, found[0])
 }
 
 // LocalBranchExists checks if a local branch exists.
@@ -160,8 +194,18 @@ func ListRemoteBranches(ctx context.Context) ([]string, error) {
 
 // DefaultBranch returns the default branch name (e.g., main, master).
 func DefaultBranch(ctx context.Context) (string, error) {
-	// Try to get from remote origin
-	cmd, err := gitCommand(ctx, "symbolic-ref", "refs/remotes/origin/HEAD", "--short")
+	remote, err := configuredRemote(ctx)
+	if err != nil {
+		return "", err
+	}
+	return RemoteDefaultBranch(ctx, remote)
+}
+
+// RemoteDefaultBranch returns the default branch name according to the HEAD of remote,
+// falling back to init.defaultBranch.
+func RemoteDefaultBranch(ctx context.Context, remote string) (string, error) {
+	// Try to get from the remote
+	cmd, err := gitCommand(ctx, "symbolic-ref", "refs/remotes/"+remote+"/HEAD", "--short")
 	if err != nil {
 		return "", err
 	}
@@ -169,7 +213,7 @@ func DefaultBranch(ctx context.Context) (string, error) {
 	if err == nil {
 		// Output is like "origin/main", extract the branch name
 		branch := strings.TrimSpace(string(out))
-		branch = strings.TrimPrefix(branch, "origin/")
+		branch = strings.TrimPrefix(branch, remote+"/")
 		return branch, nil
 	}
 
@@ -27,7 +27,7 @@ func TestBranchExists(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := BranchExists(t.Context(), tt.branch)
+			got, err := BranchExists(t.Context(), tt.branch, DefaultRemote)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -245,3 +245,98 @@ func TestDefaultBranch(t *testing.T) {
 		t.Errorf("DefaultBranch() = %q, want %q", branch, "main") //nostyle:errorstrings
 	}
 }
+
+func TestDefaultBranch_Remote(t *testing.T) {
+	upstream := testutil.NewTestRepo(t)
+	upstream.CreateFile("README.md", "# Upstream")
+	upstream.Commit("initial commit")
+	upstream.Git("checkout", "-b", "develop")
+
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+	repo.Git("config", "init.defaultBranch", "main")
+	repo.Git("remote", "add", "upstream", upstream.Root)
+	repo.Git("fetch", "upstream")
+	repo.Git("remote", "set-head", "upstream", "--auto")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	// origin does not exist, so the default branch comes from init.defaultBranch
+	branch, err := DefaultBranch(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if branch != "main" {
+		t.Errorf("DefaultBranch() = %q, want %q", branch, "main") //nostyle:errorstrings
+	}
+
+	repo.Git("config", "wt.remote", "upstream")
+	branch, err = DefaultBranch(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if branch != "develop" {
+		t.Errorf("DefaultBranch() = %q, want %q", branch, "develop") //nostyle:errorstrings
+	}
+}
+
+func TestFindRemoteBranch(t *testing.T) {
+	upstream := testutil.NewTestRepo(t)
+	upstream.CreateFile("README.md", "# Upstream")
+	upstream.Commit("initial commit")
+	upstream.Git("branch", "shared")
+	upstream.Git("branch", "upstream-only")
+
+	fork := testutil.NewTestRepo(t)
+	fork.CreateFile("README.md", "# Fork")
+	fork.Commit("initial commit")
+	fork.Git("branch", "shared")
+
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+	repo.Git("remote", "add", "upstream", upstream.Root)
+	repo.Git("remote", "add", "fork", fork.Root)
+	repo.Git("fetch", "--all")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	tests := []struct {
+		name      string
+		branch    string
+		preferred string
+		want      string
+		wantErr   bool
+	}{
+		{"single remote", "upstream-only", DefaultRemote, "upstream/upstream-only", false},
+		{"several remotes with preferred", "shared", "fork", "fork/shared", false},
+		{"several remotes without preferred", "shared", DefaultRemote, "", true},
+		{"no remote", "no-such-branch", DefaultRemote, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindRemoteBranch(t.Context(), tt.branch, tt.preferred)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindRemoteBranch(%q, %q) error = %v, wantErr %v", tt.branch, tt.preferred, err, tt.wantErr)
+			}
+			var name string
+			if got != nil {
+				name = got.String()
+			}
+			if name != tt.want {
+				t.Errorf("FindRemoteBranch(%q, %q) = %q, want %q", tt.branch, tt.preferred, name, tt.want)
+			}
+		})
+	}
+
+	exists, err := BranchExists(t.Context(), "upstream-only", DefaultRemote)
+	if err != nil {
+		t.Fatalf("BranchExists failed: %v", err)
+	}
+	if !exists {
+		t.Error("BranchExists() = false for a branch that only exists on a remote other than origin") //nostyle:errorstrings
+	}
+}
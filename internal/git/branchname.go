@@ -0,0 +1,161 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/k1LoW/exec"
+)
+
+// branchTemplateInput is the wt.branchtemplate variable replaced by the name given on the command line.
+const branchTemplateInput = "{input}"
+
+// ExpandBranchTemplate expands a wt.branchtemplate for the name given on the command line.
+// Supported variables:
+//   - {input}: name given on the command line (required)
+//   - {user}: current OS user name
+//
+// If input already has the prefix and suffix of the template (e.g., "alice/123-fix" with
+// "{user}/{input}" for user alice), it is returned as is.
+func ExpandBranchTemplate(tmpl, input string) (string, error) {
+	if strings.Count(tmpl, branchTemplateInput) != 1 {
+		return "", fmt.Errorf("invalid %s %q: %s must appear exactly once", configKeyBranchTemplate, tmpl, branchTemplateInput)
+	}
+	pairs := []string{}
+	if strings.Contains(tmpl, "{user}") {
+		name, err := currentUserName()
+		if err != nil {
+			return "", err
+		}
+		pairs = append(pairs, "{user}", sanitizePath(name))
+	}
+	expanded := strings.NewReplacer(pairs...).Replace(tmpl)
+	if v := dirNameVariable.FindString(strings.ReplaceAll(expanded, branchTemplateInput, "")); v != "" {
+		return "", fmt.Errorf("invalid %s %q: unknown variable %s", configKeyBranchTemplate, tmpl, v)
+	}
+
+	prefix, suffix, _ := strings.Cut(expanded, branchTemplateInput)
+	if strings.HasPrefix(input, prefix) && strings.HasSuffix(input, suffix) && len(input) > len(prefix)+len(suffix) {
+		return input, nil
+	}
+	return prefix + input + suffix, nil
+}
+
+// CheckBranchPattern checks that a new branch name matches wt.branchpattern (a regular expression).
+// An empty pattern accepts every name.
+func CheckBranchPattern(pattern, branch string) error {
+	if pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", configKeyBranchPattern, pattern, err)
+	}
+	if !re.MatchString(branch) {
+		return fmt.Errorf("branch name %q does not match %s %q", branch, configKeyBranchPattern, pattern)
+	}
+	return nil
+}
+
+// InvalidBranchNameError is returned by ValidateBranchName for names that cannot be used for a new branch.
+type InvalidBranchNameError struct {
+	Name   string
+	Reason string // Why the name cannot be used
+	Hint   string // How to fix it
+}
+
+func (e *InvalidBranchNameError) Error() string {
+	msg := fmt.Sprintf("invalid branch name %q: %s", e.Name, e.Reason)
+	if e.Hint != "" {
+		msg += "\n" + e.Hint
+	}
+	return msg
+}
+
+// invalidRefChars are characters that are not allowed in ref names.
+var invalidRefChars = regexp.MustCompile(`[\x00-\x20\x7f~^:?*\[\\]`)
+
+// ValidateBranchName checks that name can be used for a new branch, before anything is created.
+// It runs "git check-ref-format --branch" and checks that name does not conflict with the
+// namespace of an existing branch (e.g., "foo/bar" cannot be created while "foo" exists, and
+// vice versa). The error is an *InvalidBranchNameError.
+func ValidateBranchName(ctx context.Context, name string) error {
+	if reason := invalidRefReason(name); reason != "" {
+		return &InvalidBranchNameError{Name: name, Reason: reason, Hint: "See 'git help check-ref-format' for the rules of branch names."}
+	}
+	cmd, err := gitCommand(ctx, "check-ref-format", "--branch", name)
+	if err != nil {
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return fmt.Errorf("failed to check branch name: %w", err)
+		}
+		return &InvalidBranchNameError{Name: name, Reason: "rejected by git check-ref-format: " + strings.TrimSpace(string(out)), Hint: "See 'git help check-ref-format' for the rules of branch names."}
+	}
+
+	cmd, err = gitCommand(ctx, "for-each-ref", "--format=%(refname:lstrip=2)", "refs/heads/")
+	if err != nil {
+		return err
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to list branches: %w", err)
+	}
+	for _, b := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if b == "" {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(name, b+"/"):
+			return &InvalidBranchNameError{
+				Name:   name,
+				Reason: fmt.Sprintf("branch %q exists, so no branch can be created under %q", b, b+"/"),
+				Hint:   fmt.Sprintf("Use another name (e.g., %q), or rename or delete branch %q.", strings.ReplaceAll(name, "/", "-"), b),
+			}
+		case strings.HasPrefix(b, name+"/"):
+			return &InvalidBranchNameError{
+				Name:   name,
+				Reason: fmt.Sprintf("branch %q exists under %q", b, name+"/"),
+				Hint:   fmt.Sprintf("Use another name (e.g., %q), or rename or delete the branches under %q.", name+"-main", name+"/"),
+			}
+		}
+	}
+	return nil
+}
+
+// invalidRefReason returns why name is not a valid branch name for the common mistakes,
+// or an empty string. "git check-ref-format --branch" checks the remaining rules.
+func invalidRefReason(name string) string {
+	switch {
+	case name == "":
+		return "name is empty"
+	case name == "HEAD" || name == "@":
+		return fmt.Sprintf("%q is reserved", name)
+	case strings.HasPrefix(name, "-"):
+		return `must not start with "-"`
+	case strings.Contains(name, ".."):
+		return `must not contain ".."`
+	case strings.Contains(name, "@{"):
+		return `must not contain "@{"`
+	case strings.Contains(name, "//"):
+		return `must not contain "//"`
+	case strings.HasSuffix(name, "/") || strings.HasSuffix(name, "."):
+		return `must not end with "/" or "."`
+	case invalidRefChars.MatchString(name):
+		return `must not contain spaces, control characters or any of ~^:?*[\`
+	}
+	for _, component := range strings.Split(name, "/") {
+		if strings.HasPrefix(component, ".") {
+			return fmt.Sprintf(`component %q must not start with "."`, component)
+		}
+		if strings.HasSuffix(component, ".lock") {
+			return fmt.Sprintf(`component %q must not end with ".lock"`, component)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,162 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestExpandBranchTemplate(t *testing.T) {
+	tests := []struct {
+		tmpl    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"{input}", "feature", "feature", false},
+		{"fix/{input}", "123-crash", "fix/123-crash", false},
+		{"fix/{input}", "fix/123-crash", "fix/123-crash", false},
+		{"fix/{input}", "fix/", "fix/fix/", false},
+		{"{input}-wip", "login", "login-wip", false},
+		{"{input}-wip", "login-wip", "login-wip", false},
+		{"fix/x", "login", "", true},
+		{"{input}/{input}", "login", "", true},
+		{"{team}/{input}", "login", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ExpandBranchTemplate(tt.tmpl, tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ExpandBranchTemplate(%q, %q) error = %v, wantErr %v", tt.tmpl, tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ExpandBranchTemplate(%q, %q) = %q, want %q", tt.tmpl, tt.input, got, tt.want)
+		}
+	}
+
+	t.Run("user", func(t *testing.T) {
+		name, err := currentUserName()
+		if err != nil {
+			t.Skipf("no user name: %v", err)
+		}
+		want := sanitizePath(name) + "/123-crash"
+		for _, input := range []string{"123-crash", want} {
+			got, err := ExpandBranchTemplate("{user}/{input}", input)
+			if err != nil {
+				t.Fatalf("ExpandBranchTemplate() failed: %v", err)
+			}
+			if got != want {
+				t.Errorf("ExpandBranchTemplate(%q) = %q, want %q", input, got, want)
+			}
+		}
+	})
+}
+
+func TestCheckBranchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		branch  string
+		wantErr bool
+	}{
+		{"", "anything", false},
+		{`^[a-z]+/[0-9]+-[a-z-]+$`, "alice/123-fix-crash", false},
+		{`^[a-z]+/[0-9]+-[a-z-]+$`, "fix-crash", true},
+		{`^(feature|fix)/`, "feature/login", false},
+		{`[`, "feature/login", true},
+	}
+	for _, tt := range tests {
+		err := CheckBranchPattern(tt.pattern, tt.branch)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckBranchPattern(%q, %q) error = %v, wantErr %v", tt.pattern, tt.branch, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateBranchName(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+	repo.Git("branch", "foo")
+	repo.Git("branch", "team/alice")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	tests := []struct {
+		name    string
+		branch  string
+		wantErr bool
+	}{
+		{"simple", "feature", false},
+		{"nested", "feature/login", false},
+		{"unicode", "機能/ログイン", false},
+		{"prefix_of_existing_not_component", "fo", false},
+		{"sibling_of_namespace", "team-bob", false},
+		{"empty", "", true},
+		{"double_dot", "foo..bar", true},
+		{"lock_suffix", "topic.lock", true},
+		{"lock_component", "topic.lock/x", true},
+		{"trailing_slash", "topic/", true},
+		{"trailing_dot", "topic.", true},
+		{"leading_dot", ".topic", true},
+		{"leading_dot_component", "team/.topic", true},
+		{"leading_dash", "-topic", true},
+		{"head", "HEAD", true},
+		{"at", "@", true},
+		{"reflog_syntax", "@{-1}", true},
+		{"at_brace", "topic@{1}", true},
+		{"space", "my topic", true},
+		{"tilde", "topic~1", true},
+		{"caret", "topic^", true},
+		{"colon", "a:b", true},
+		{"question", "topic?", true},
+		{"asterisk", "topic*", true},
+		{"bracket", "topic[1]", true},
+		{"backslash", `team\topic`, true},
+		{"double_slash", "team//topic", true},
+		{"under_existing_branch", "foo/bar", true},
+		{"parent_of_existing_branch", "team", true},
+		{"existing_namespace_deeper", "foo/bar/baz", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBranchName(t.Context(), tt.branch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateBranchName(%q) error = %v, wantErr %v", tt.branch, err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			var nameErr *InvalidBranchNameError
+			if !errors.As(err, &nameErr) {
+				t.Fatalf("ValidateBranchName(%q) error = %T, want *InvalidBranchNameError", tt.branch, err)
+			}
+			if nameErr.Name != tt.branch || nameErr.Reason == "" || nameErr.Hint == "" {
+				t.Errorf("ValidateBranchName(%q) error = %+v, want name, reason and hint", tt.branch, nameErr)
+			}
+		})
+	}
+}
+
+func TestAddWorktreeWithNewBranch_InvalidName(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	baseDir := filepath.Join(repo.ParentDir(), "wt")
+	err := AddWorktreeWithNewBranch(t.Context(), filepath.Join(baseDir, "foo..bar"), "foo..bar", "", CopyOptions{})
+	var nameErr *InvalidBranchNameError
+	if !errors.As(err, &nameErr) {
+		t.Fatalf("AddWorktreeWithNewBranch() error = %v, want *InvalidBranchNameError", err)
+	}
+	// Nothing is created
+	if _, err := os.Stat(baseDir); !os.IsNotExist(err) {
+		t.Errorf("base directory should not be created: %v", err)
+	}
+}
@@ -3,60 +3,144 @@ package git
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
+	"os/user"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/k1LoW/exec"
 )
 
 const (
-	configKeyBaseDir       = "wt.basedir"
-	configKeyCopyIgnored   = "wt.copyignored"
-	configKeyCopyUntracked = "wt.copyuntracked"
-	configKeyCopyModified  = "wt.copymodified"
-	configKeyNoCopy        = "wt.nocopy"
-	configKeyCopy          = "wt.copy"
-	configKeyHook          = "wt.hook"
-	configKeyNoCd          = "wt.nocd"
-	configKeyRelative      = "wt.relative"
+	configKeyBaseDir        = "wt.basedir"
+	configKeyCopyIgnored    = "wt.copyignored"
+	configKeyCopyUntracked  = "wt.copyuntracked"
+	configKeyCopyModified   = "wt.copymodified"
+	configKeyNoCopy         = "wt.nocopy"
+	configKeyCopy           = "wt.copy"
+	configKeyLink           = "wt.link"
+	configKeyHardLink       = "wt.hardlink"
+	configKeyFollowSymlinks = "wt.copyfollowsymlinks"
+	configKeyCopyFrom       = "wt.copyfrom"
+	configKeyTemplate       = "wt.template"
+	configKeyCopySource     = "wt.copysource"
+	configKeyPortBlock      = "wt.portblock"
+	configKeyPortBase       = "wt.portbase"
+	configKeyHook           = "wt.hook"
+	configKeyNoCd           = "wt.nocd"
+	configKeyRelative       = "wt.relative"
+	configKeyTrustedHooks   = "wt.trustedhooks"
+	configKeyDefaultProfile = "wt.defaultprofile"
+	configKeyDirName        = "wt.dirname"
+	configKeyBranchTemplate = "wt.branchtemplate"
+	configKeyBranchPattern  = "wt.branchpattern"
+	configKeyCreate         = "wt.create"
+	configKeyRemote         = "wt.remote"
+	configKeyStartPoint     = "wt.startpoint"
+	configKeyFetch          = "wt.fetch"
+	configKeyForge          = "wt.forge"
+)
+
+// wt.create modes.
+const (
+	CreateAuto    = "auto"    // Create branches that do not exist (default)
+	CreateConfirm = "confirm" // Ask before creating a branch (only on a terminal)
+	CreateNever   = "never"   // Never create branches implicitly
+)
+
+// wt.nocd modes, which tell the shell integration whether to change directory to the worktree.
+const (
+	NoCdOff    = "false"  // Always change directory (default)
+	NoCdAll    = "all"    // Never change directory (also set by true)
+	NoCdCreate = "create" // Change directory only to existing worktrees
 )
 
 // Config holds all wt configuration values.
 type Config struct {
-	BaseDir       string
-	CopyIgnored   bool
-	CopyUntracked bool
-	CopyModified  bool
-	NoCopy        []string
-	Copy          []string
-	Hooks         []string
-	NoCd          bool
-	Relative      bool
+	BaseDir        string
+	DirName        string // Template of worktree directory names, relative to BaseDir (see ExpandDirName)
+	BranchTemplate string // Template of new branch names (see ExpandBranchTemplate)
+	BranchPattern  string // Regular expression that new branch names must match
+	Create         string // Whether branches that do not exist are created: CreateAuto, CreateConfirm or CreateNever
+	Remote         string // Remote preferred when several remotes have a branch, and whose HEAD is the default branch
+	StartPoint     string // Template of the start point of new branches (see ExpandStartPoint), HEAD if empty
+	Fetch          bool   // Fetch the remote branch of the start point before creating a new branch
+	Forge          string // Layout of pull request refs on the remote: ForgeGitHub or ForgeGitLab
+	CopyIgnored    bool
+	CopyUntracked  bool
+	CopyModified   bool
+	NoCopy         []string
+	Copy           []string
+	Link           []string
+	HardLink       []string
+	FollowSymlinks bool
+	CopyFrom       string
+	Template       []string
+	CopySource     string
+	PortBlock      int // Number of ports allocated to each new worktree (0 disables allocation)
+	PortBase       int // First port of the port registry
+	Hooks          []string
+	RepoHooks      []string // Hooks from the repository config file (.gitwt), run only after they are trusted
+	Warnings       []string // Invalid values and unknown keys found while loading
+	NoCd           string   // Whether the shell integration changes directory: NoCdOff, NoCdAll or NoCdCreate
+	Relative       bool
+	DefaultProfile string // Profile applied when none is given with --profile
+	Profile        string // Applied profile ([wt "profile.<name>"]), empty if none
 }
 
 // GitConfig retrieves all git config values for a key.
+// wt.* keys are read from the config snapshot, which is cached in contexts created by WithConfigCache.
 func GitConfig(ctx context.Context, key string) ([]string, error) { //nolint:revive //nostyle:repetition
-	cmd, err := gitCommand(ctx, "config", "--get-all", key)
+	if !strings.HasPrefix(key, "wt.") {
+		return gitConfigValues(ctx, "config", "--get-all", key)
+	}
+	snapshot, err := loadConfigSnapshot(ctx)
 	if err != nil {
 		return nil, err
 	}
-	out, err := cmd.Output()
+	_, gitValues := snapshot.sourceValues(key)
+	var values []string
+	for _, v := range gitValues {
+		values = append(values, v.value)
+	}
+	return values, nil
+}
+
+// gitConfigValues runs a "git config" command and returns its values.
+func gitConfigValues(ctx context.Context, args ...string) ([]string, error) {
+	out, err := gitConfigOutput(ctx, args...)
 	if err != nil {
-		// git config returns exit code 1 if key is not found
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
-			return nil, nil
-		}
 		return nil, err
 	}
-	trimmed := strings.TrimSpace(string(out))
+	trimmed := strings.TrimSpace(out)
 	if trimmed == "" {
 		return nil, nil
 	}
 	return strings.Split(trimmed, "\n"), nil
 }
 
+// gitConfigOutput runs a "git config" command and returns its output.
+// A key that is not found results in empty output.
+func gitConfigOutput(ctx context.Context, args ...string) (string, error) {
+	cmd, err := gitCommand(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		// git config returns exit code 1 if key is not found
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", err
+	}
+	return string(out), nil
+}
+
 // ShowPrefix returns the path prefix of the current directory relative to the repository root.
 // It runs "git rev-parse --show-prefix" and strips the trailing slash.
 // Returns an empty string when at the repository root.
@@ -111,83 +195,96 @@ func RepoName(ctx context.Context) (string, error) {
 	return filepath.Base(root), nil
 }
 
-// LoadConfig loads configuration from git config with default values.
+// LoadConfig loads configuration with default values.
+// Values are merged from the repository config file (.gitwt), then git config (system, global, local).
+// Hooks from the repository config file are kept separately in RepoHooks.
+// Values are parsed with git's rules (e.g., yes/on/1 are true); invalid values and unknown
+// wt.* keys are reported in Warnings instead of failing.
 func LoadConfig(ctx context.Context) (Config, error) {
-	cfg := Config{}
-
-	// BaseDir
-	baseDir, err := GitConfig(ctx, configKeyBaseDir)
-	if err != nil {
-		return cfg, err
-	}
-	if len(baseDir) == 0 {
-		cfg.BaseDir = ".wt"
-	} else {
-		cfg.BaseDir = baseDir[len(baseDir)-1]
-	}
-
-	// CopyIgnored
-	val, err := GitConfig(ctx, configKeyCopyIgnored)
-	if err != nil {
-		return cfg, err
-	}
-	cfg.CopyIgnored = len(val) > 0 && val[len(val)-1] == "true"
-
-	// CopyUntracked
-	val, err = GitConfig(ctx, configKeyCopyUntracked)
-	if err != nil {
-		return cfg, err
-	}
-	cfg.CopyUntracked = len(val) > 0 && val[len(val)-1] == "true"
-
-	// CopyModified
-	val, err = GitConfig(ctx, configKeyCopyModified)
-	if err != nil {
-		return cfg, err
-	}
-	cfg.CopyModified = len(val) > 0 && val[len(val)-1] == "true"
+	return LoadConfigFor(ctx, "", "")
+}
 
-	// NoCopy
-	noCopy, err := GitConfig(ctx, configKeyNoCopy)
+// LoadConfigFor loads configuration like LoadConfig for branch and profile.
+// Values in [wt "<pattern>"] subsections whose glob pattern matches branch (e.g., [wt "release/*"])
+// are applied: for each key, the most specific matching pattern that sets it wins over the values
+// outside of subsections. An exact branch name wins over globs, and globs with more literal
+// characters win over globs with fewer.
+// Then, values in the subsection of profile ([wt "profile.<name>"]), or of wt.defaultprofile if
+// profile is empty, win over both. An unknown profile is an error.
+func LoadConfigFor(ctx context.Context, branch, profile string) (Config, error) {
+	snapshot, err := loadConfigSnapshot(ctx)
 	if err != nil {
-		return cfg, err
+		return Config{}, err
 	}
-	cfg.NoCopy = noCopy
-
-	// Copy
-	copyPatterns, err := GitConfig(ctx, configKeyCopy)
-	if err != nil {
-		return cfg, err
+	l := &configLoader{snapshot: snapshot, branch: branch}
+	defaultProfile := l.stringValue(configKeyDefaultProfile, "")
+	switch {
+	case profile != "":
+		if !slices.Contains(snapshot.profiles(), profile) {
+			return Config{}, fmt.Errorf("unknown profile %q (no wt.profile.%s.* config)", profile, profile)
+		}
+		l.profile = profile
+	case defaultProfile != "":
+		if slices.Contains(snapshot.profiles(), defaultProfile) {
+			l.profile = defaultProfile
+		} else {
+			l.warn("invalid %s: unknown profile %q (ignored)", configKeyDefaultProfile, defaultProfile)
+		}
 	}
-	cfg.Copy = copyPatterns
 
-	// Hooks
-	hooks, err := GitConfig(ctx, configKeyHook)
-	if err != nil {
-		return cfg, err
+	cfg := Config{
+		BaseDir:        l.stringValue(configKeyBaseDir, ".wt"),
+		DirName:        l.stringValue(configKeyDirName, DefaultDirName),
+		BranchTemplate: l.stringValue(configKeyBranchTemplate, ""),
+		BranchPattern:  l.stringValue(configKeyBranchPattern, ""),
+		Create:         l.createValue(),
+		Remote:         l.stringValue(configKeyRemote, DefaultRemote),
+		StartPoint:     l.stringValue(configKeyStartPoint, ""),
+		Fetch:          l.boolValue(configKeyFetch),
+		Forge:          l.forgeValue(),
+		CopyIgnored:    l.boolValue(configKeyCopyIgnored),
+		CopyUntracked:  l.boolValue(configKeyCopyUntracked),
+		CopyModified:   l.boolValue(configKeyCopyModified),
+		NoCopy:         l.listValue(configKeyNoCopy),
+		Copy:           l.listValue(configKeyCopy),
+		Link:           l.listValue(configKeyLink),
+		HardLink:       l.listValue(configKeyHardLink),
+		FollowSymlinks: l.boolValue(configKeyFollowSymlinks),
+		CopyFrom:       l.stringValue(configKeyCopyFrom, ""),
+		Template:       l.listValue(configKeyTemplate),
+		CopySource:     l.stringValue(configKeyCopySource, ""),
+		PortBlock:      l.intValue(configKeyPortBlock, 0),
+		PortBase:       l.intValue(configKeyPortBase, DefaultPortBase),
+		NoCd:           l.noCdValue(),
+		Relative:       l.boolValue(configKeyRelative),
+		DefaultProfile: defaultProfile,
+		Profile:        l.profile,
 	}
-	cfg.Hooks = hooks
+	cfg.Hooks, cfg.RepoHooks = l.hookValues()
+	l.checkUnknownKeys()
 
-	// NoCd
-	val, err = GitConfig(ctx, configKeyNoCd)
-	if err != nil {
-		return cfg, err
-	}
-	cfg.NoCd = len(val) > 0 && val[len(val)-1] == "true"
+	cfg.Warnings = l.warnings
+	return cfg, nil
+}
 
-	// Relative
-	val, err = GitConfig(ctx, configKeyRelative)
+// ConfigProfiles returns the names of the configured profiles ([wt "profile.<name>"]).
+func ConfigProfiles(ctx context.Context) ([]string, error) {
+	snapshot, err := loadConfigSnapshot(ctx)
 	if err != nil {
-		return cfg, err
+		return nil, err
 	}
-	cfg.Relative = len(val) > 0 && val[len(val)-1] == "true"
-
-	return cfg, nil
+	return snapshot.profiles(), nil
 }
 
+// envVariable matches {env:VAR} template variables.
+var envVariable = regexp.MustCompile(`\{env:([^}]*)\}`)
+
 // expandTemplate expands template variables in a string.
 // Supported variables:
 //   - {gitroot}: repository root directory name
+//   - {host}, {owner}, {repo}: parts of the remote URL (wt.remote, or the first remote),
+//     e.g., github.com, k1LoW and git-wt for git@github.com:k1LoW/git-wt.git
+//   - {env:VAR}: value of the environment variable VAR
 func expandTemplate(ctx context.Context, s string) (string, error) {
 	// Expand {gitroot}
 	if strings.Contains(s, "{gitroot}") {
@@ -198,13 +295,36 @@ func expandTemplate(ctx context.Context, s string) (string, error) {
 		s = strings.ReplaceAll(s, "{gitroot}", repoName)
 	}
 
+	// Expand {host}, {owner} and {repo}
+	if strings.Contains(s, "{host}") || strings.Contains(s, "{owner}") || strings.Contains(s, "{repo}") {
+		remote, err := repoRemoteURL(ctx)
+		if err != nil {
+			return "", err
+		}
+		s = strings.NewReplacer("{host}", remote.Host, "{owner}", remote.Owner, "{repo}", remote.Repo).Replace(s)
+	}
+
+	// Expand {env:VAR}
+	var envErr error
+	s = envVariable.ReplaceAllStringFunc(s, func(m string) string {
+		name := envVariable.FindStringSubmatch(m)[1]
+		v, ok := os.LookupEnv(name)
+		if !ok && envErr == nil {
+			envErr = fmt.Errorf("failed to expand %s: environment variable %s is not set", m, name)
+		}
+		return v
+	})
+	if envErr != nil {
+		return "", envErr
+	}
+
 	return s, nil
 }
 
-// ExpandPath expands ~ to home directory and resolves relative paths.
+// ExpandPath expands ~ and ~user to home directories and resolves relative paths.
 // Relative paths are resolved from the main repository root, not the current worktree.
 func ExpandPath(ctx context.Context, path string) (string, error) {
-	// Expand ~
+	// Expand ~ and ~user like "git config --type=path"
 	if strings.HasPrefix(path, "~/") {
 		home, err := os.UserHomeDir()
 		if err != nil {
@@ -213,6 +333,13 @@ func ExpandPath(ctx context.Context, path string) (string, error) {
 		path = filepath.Join(home, path[2:])
 	} else if path == "~" {
 		return os.UserHomeDir()
+	} else if strings.HasPrefix(path, "~") {
+		name, rest, _ := strings.Cut(path[1:], "/")
+		u, err := user.Lookup(name)
+		if err != nil {
+			return "", fmt.Errorf("failed to expand %q: %w", path, err)
+		}
+		path = filepath.Join(u.HomeDir, rest)
 	}
 
 	// If already absolute, return as is
@@ -245,17 +372,18 @@ func ExpandBaseDir(ctx context.Context, baseDir string) (string, error) {
 	return expanded, nil
 }
 
-// IsBaseDirConfigured checks if wt.basedir is explicitly configured in git config.
+// IsBaseDirConfigured checks if wt.basedir is explicitly configured in git config or the repository config file.
 func IsBaseDirConfigured(ctx context.Context) (bool, error) {
-	baseDir, err := GitConfig(ctx, configKeyBaseDir)
+	snapshot, err := loadConfigSnapshot(ctx)
 	if err != nil {
 		return false, err
 	}
-	return len(baseDir) > 0, nil
+	return len(snapshot.values(configKeyBaseDir)) > 0, nil
 }
 
 // SetConfig sets a git config value.
 func SetConfig(ctx context.Context, key, value string) error {
+	defer invalidateConfigCache(ctx)
 	cmd, err := gitCommand(ctx, "config", key, value)
 	if err != nil {
 		return err
@@ -263,12 +391,17 @@ func SetConfig(ctx context.Context, key, value string) error {
 	return cmd.Run()
 }
 
-// WorktreePathFor returns the full path for a worktree given a base directory pattern and branch name.
-func WorktreePathFor(ctx context.Context, baseDir, branch string) (string, error) {
+// WorktreePathFor returns the full path for a worktree given a base directory pattern,
+// a directory name template (wt.dirname) and branch name.
+func WorktreePathFor(ctx context.Context, baseDir, dirName, branch string) (string, error) {
 	expandedBaseDir, err := ExpandBaseDir(ctx, baseDir)
 	if err != nil {
 		return "", err
 	}
+	name, err := ExpandDirName(dirName, branch)
+	if err != nil {
+		return "", err
+	}
 
-	return filepath.Join(expandedBaseDir, branch), nil
+	return filepath.Join(expandedBaseDir, name), nil
 }
@@ -0,0 +1,291 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// profileSubsectionPrefix prefixes the config subsections of named profiles ([wt "profile.<name>"]).
+const profileSubsectionPrefix = "profile."
+
+// configEntry is a raw wt.* config value with the scope it was read from
+// (a git config scope such as local or global, or OriginFile for the repository config file).
+type configEntry struct {
+	name  string
+	scope string
+	configValue
+}
+
+// configSnapshot holds all wt.* config values, read with one git invocation per source.
+type configSnapshot struct {
+	dir      string        // Working directory the snapshot was read in
+	repoFile string        // Path of the repository config file (.gitwt), empty if none
+	entries  []configEntry // Repository config file first, then git config in precedence order
+	ignored  []string      // Names of the keys ignored in the repository config file (see repoConfigKeys)
+}
+
+// values returns the raw values of key in precedence order (the last value wins).
+func (s *configSnapshot) values(key string) []configValue {
+	var values []configValue
+	for _, e := range s.entries {
+		if e.name == key {
+			values = append(values, e.configValue)
+		}
+	}
+	return values
+}
+
+// sourceValues returns the raw values of key from the repository config file and from git config.
+func (s *configSnapshot) sourceValues(key string) ([]configValue, []configValue) {
+	var repoValues, gitValues []configValue
+	for _, e := range s.entries {
+		if e.name != key {
+			continue
+		}
+		if e.scope == OriginFile {
+			repoValues = append(repoValues, e.configValue)
+		} else {
+			gitValues = append(gitValues, e.configValue)
+		}
+	}
+	return repoValues, gitValues
+}
+
+// entriesFor returns the entries of key that apply to branch, in precedence order.
+// Entries in subsections whose pattern matches branch (e.g., [wt "release/*"]) replace the
+// entries outside of subsections; the most specific matching pattern that sets key wins.
+func (s *configSnapshot) entriesFor(key, branch string) []configEntry {
+	var global []configEntry
+	scoped := make(map[string][]configEntry)
+	var patterns []string
+	for _, e := range s.entries {
+		pattern, k := splitConfigName(e.name)
+		if k != key {
+			continue
+		}
+		if pattern == "" {
+			global = append(global, e)
+			continue
+		}
+		if branch == "" || isProfileSubsection(pattern) || !matchBranchPattern(pattern, branch) {
+			continue
+		}
+		if _, ok := scoped[pattern]; !ok {
+			patterns = append(patterns, pattern)
+		}
+		scoped[pattern] = append(scoped[pattern], e)
+	}
+	if len(patterns) == 0 {
+		return global
+	}
+	sort.SliceStable(patterns, func(i, j int) bool {
+		return patternSpecificity(patterns[i]) < patternSpecificity(patterns[j])
+	})
+	return scoped[patterns[len(patterns)-1]]
+}
+
+// profileEntries returns the entries of key in the subsection of profile ([wt "profile.<name>"]),
+// in precedence order.
+func (s *configSnapshot) profileEntries(key, profile string) []configEntry {
+	var entries []configEntry
+	for _, e := range s.entries {
+		if subsection, k := splitConfigName(e.name); k == key && subsection == profileSubsectionPrefix+profile {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// profiles returns the names of the configured profiles, in order of appearance.
+func (s *configSnapshot) profiles() []string {
+	var profiles []string
+	for _, e := range s.entries {
+		subsection, _ := splitConfigName(e.name)
+		if !isProfileSubsection(subsection) {
+			continue
+		}
+		if name := strings.TrimPrefix(subsection, profileSubsectionPrefix); !slices.Contains(profiles, name) {
+			profiles = append(profiles, name)
+		}
+	}
+	return profiles
+}
+
+// isProfileSubsection reports whether a config subsection holds a named profile
+// ([wt "profile.<name>"]) rather than a branch pattern.
+func isProfileSubsection(subsection string) bool {
+	return strings.HasPrefix(subsection, profileSubsectionPrefix) && len(subsection) > len(profileSubsectionPrefix)
+}
+
+// splitConfigName splits a config name into its subsection (a branch pattern or profile) and key
+// without subsection, e.g., "wt.release/*.basedir" -> ("release/*", "wt.basedir"). The subsection
+// is empty for names without subsection.
+func splitConfigName(name string) (string, string) {
+	rest, ok := strings.CutPrefix(name, "wt.")
+	if !ok {
+		return "", name
+	}
+	i := strings.LastIndex(rest, ".")
+	if i < 0 {
+		return "", name
+	}
+	return rest[:i], "wt." + rest[i+1:]
+}
+
+// matchBranchPattern reports whether branch matches the glob pattern of a config subsection.
+// Wildcards do not match "/" (e.g., "release/*" matches "release/1.0" but not "release/1.0/hotfix").
+func matchBranchPattern(pattern, branch string) bool {
+	ok, err := path.Match(pattern, branch)
+	return err == nil && ok
+}
+
+// patternSpecificity ranks branch patterns: exact names rank above all globs,
+// and globs with more literal characters rank above globs with fewer.
+func patternSpecificity(pattern string) int {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return math.MaxInt
+	}
+	n := 0
+	for _, r := range pattern {
+		if r != '*' && r != '?' {
+			n++
+		}
+	}
+	return n
+}
+
+// configCacheKey is the context key of the config cache.
+type configCacheKey struct{}
+
+// configCache caches the config snapshot for the lifetime of a context.
+type configCache struct {
+	mu       sync.Mutex
+	snapshot *configSnapshot
+	reported map[string]struct{} // Config warnings already returned by NewConfigWarnings
+}
+
+// WithConfigCache returns a context in which wt.* config is read once and reused,
+// e.g., for the lifetime of a command. The cache is invalidated when config is written
+// with SetConfig, SetConfigValues, UnsetConfig or TrustHooks.
+func WithConfigCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, configCacheKey{}, &configCache{})
+}
+
+// loadConfigSnapshot reads all wt.* config values, using the cache of ctx if any.
+func loadConfigSnapshot(ctx context.Context) (*configSnapshot, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	cache, _ := ctx.Value(configCacheKey{}).(*configCache)
+	if cache != nil {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		if cache.snapshot != nil && cache.snapshot.dir == dir {
+			return cache.snapshot, nil
+		}
+	}
+
+	s := &configSnapshot{dir: dir}
+	s.repoFile, err = RepoConfigPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.repoFile != "" {
+		entries, err := readConfigEntries(ctx, "-f", s.repoFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", s.repoFile, err)
+		}
+		for _, e := range entries {
+			// Unknown keys are kept, so that they are reported like in git config
+			if isKnownConfigKey(e.name) && !isRepoConfigKey(e.name) {
+				s.ignored = append(s.ignored, e.name)
+				continue
+			}
+			e.scope = OriginFile
+			s.entries = append(s.entries, e)
+		}
+	}
+	entries, err := readConfigEntries(ctx, "--show-scope")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git config: %w", err)
+	}
+	s.entries = append(s.entries, entries...)
+
+	if cache != nil {
+		cache.snapshot = s
+	}
+	return s, nil
+}
+
+// NewConfigWarnings returns the warnings that have not been returned before for ctx, so that
+// loading config several times (e.g., for several branches) reports each warning once.
+// Without a config cache, all warnings are new.
+func NewConfigWarnings(ctx context.Context, warnings []string) []string {
+	cache, ok := ctx.Value(configCacheKey{}).(*configCache)
+	if !ok {
+		return warnings
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.reported == nil {
+		cache.reported = make(map[string]struct{})
+	}
+	var result []string
+	for _, w := range warnings {
+		if _, ok := cache.reported[w]; ok {
+			continue
+		}
+		cache.reported[w] = struct{}{}
+		result = append(result, w)
+	}
+	return result
+}
+
+// invalidateConfigCache discards the cached config snapshot of ctx after config is written.
+func invalidateConfigCache(ctx context.Context) {
+	if cache, ok := ctx.Value(configCacheKey{}).(*configCache); ok {
+		cache.mu.Lock()
+		cache.snapshot = nil
+		cache.mu.Unlock()
+	}
+}
+
+// readConfigEntries reads all wt.* config values with "git config --null --get-regexp".
+// With --show-scope, each entry is preceded by its scope.
+func readConfigEntries(ctx context.Context, args ...string) ([]configEntry, error) {
+	withScope := len(args) > 0 && args[0] == "--show-scope"
+	args = append([]string{"config", "--null"}, args...)
+	out, err := gitConfigOutput(ctx, append(args, "--get-regexp", `^wt\.`)...)
+	if err != nil {
+		return nil, err
+	}
+	records := strings.Split(out, "\x00")
+	var entries []configEntry
+	for i := 0; i < len(records); i++ {
+		var e configEntry
+		if withScope {
+			if i+1 >= len(records) {
+				break
+			}
+			e.scope = records[i]
+			i++
+		}
+		if records[i] == "" {
+			continue
+		}
+		// Each record is "<name>\n<value>", or "<name>" for a key without value
+		name, value, ok := strings.Cut(records[i], "\n")
+		e.name = name
+		e.configValue = configValue{value: value, noValue: !ok}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
@@ -0,0 +1,183 @@
+package git
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestReadConfigEntries(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+	repo.Git("config", "--add", "wt.copy", "multi\nline")
+	repo.Git("config", "wt.nocd", "")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	entries, err := readConfigEntries(t.Context(), "--show-scope")
+	if err != nil {
+		t.Fatalf("readConfigEntries failed: %v", err)
+	}
+	want := []configEntry{
+		{name: "wt.copy", scope: "local", configValue: configValue{value: "multi\nline"}},
+		{name: "wt.nocd", scope: "local", configValue: configValue{value: ""}},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("readConfigEntries() = %+v, want %+v", entries, want)
+	}
+}
+
+func TestWithConfigCache(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+	repo.Git("config", "wt.basedir", "../first")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	ctx := WithConfigCache(t.Context())
+	if _, err := LoadConfig(ctx); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	// Cached config is reused without running git
+	before := gitCommandCount.Load()
+	cfg, err := LoadConfig(ctx)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if n := gitCommandCount.Load() - before; n != 0 {
+		t.Errorf("LoadConfig ran %d git commands with a cached config, want 0", n)
+	}
+	if cfg.BaseDir != "../first" {
+		t.Errorf("LoadConfig().BaseDir = %q, want %q", cfg.BaseDir, "../first") //nostyle:errorstrings
+	}
+
+	// Writing config invalidates the cache
+	if err := SetConfigValues(ctx, "wt.basedir", []string{"../second"}, false); err != nil {
+		t.Fatalf("SetConfigValues failed: %v", err)
+	}
+	cfg, err = LoadConfig(ctx)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.BaseDir != "../second" {
+		t.Errorf("LoadConfig().BaseDir = %q after set, want %q", cfg.BaseDir, "../second") //nostyle:errorstrings
+	}
+}
+
+func TestNewConfigWarnings(t *testing.T) {
+	warnings := []string{"invalid wt.portblock", "unknown config key"}
+
+	// Without a config cache, all warnings are new
+	if got := NewConfigWarnings(t.Context(), warnings); !reflect.DeepEqual(got, warnings) {
+		t.Errorf("NewConfigWarnings() without cache = %v, want %v", got, warnings)
+	}
+
+	ctx := WithConfigCache(t.Context())
+	if got := NewConfigWarnings(ctx, warnings); !reflect.DeepEqual(got, warnings) {
+		t.Errorf("NewConfigWarnings() = %v, want %v", got, warnings)
+	}
+	// Warnings already returned for the context are not returned again
+	if got := NewConfigWarnings(ctx, append(warnings, "invalid wt.nocd")); !reflect.DeepEqual(got, []string{"invalid wt.nocd"}) {
+		t.Errorf("NewConfigWarnings() = %v, want [invalid wt.nocd]", got)
+	}
+	// Each context reports warnings separately
+	if got := NewConfigWarnings(WithConfigCache(t.Context()), warnings); !reflect.DeepEqual(got, warnings) {
+		t.Errorf("NewConfigWarnings() with another context = %v, want %v", got, warnings)
+	}
+}
+
+// benchmarkDeleteConfigLookups performs the config lookups of "git wt -d a b c":
+// the command loads config, then resolves each target and its directory name.
+func benchmarkDeleteConfigLookups(ctx context.Context, b *testing.B) {
+	b.Helper()
+	if _, err := LoadConfig(ctx); err != nil {
+		b.Fatalf("LoadConfig failed: %v", err)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		wt, err := FindWorktreeByBranchOrDir(ctx, name)
+		if err != nil {
+			b.Fatalf("FindWorktreeByBranchOrDir failed: %v", err)
+		}
+		if _, err := WorktreeDirName(ctx, wt); err != nil {
+			b.Fatalf("WorktreeDirName failed: %v", err)
+		}
+	}
+}
+
+func setupConfigBenchmark(b *testing.B) func() {
+	b.Helper()
+	repo := testutil.NewTestRepo(b)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+	repo.Git("config", "wt.copyignored", "true")
+	repo.Git("config", "--add", "wt.nocopy", "*.log")
+	for _, name := range []string{"a", "b", "c"} {
+		repo.Git("worktree", "add", "-b", name, filepath.Join(repo.Root, ".wt", name))
+	}
+	return repo.Chdir()
+}
+
+func reportGitCommands(b *testing.B, before int64) {
+	b.Helper()
+	b.ReportMetric(float64(gitCommandCount.Load()-before)/float64(b.N), "git/op")
+}
+
+// BenchmarkLoadConfig_PerKey measures the previous approach of one "git config --get-all" per key.
+func BenchmarkLoadConfig_PerKey(b *testing.B) {
+	restore := setupConfigBenchmark(b)
+	defer restore()
+
+	before := gitCommandCount.Load()
+	b.ResetTimer()
+	for range b.N {
+		for _, k := range configKinds {
+			if _, err := gitConfigValues(b.Context(), "config", "--get-all", k.key); err != nil {
+				b.Fatalf("git config failed: %v", err)
+			}
+		}
+	}
+	reportGitCommands(b, before)
+}
+
+func BenchmarkLoadConfig(b *testing.B) {
+	restore := setupConfigBenchmark(b)
+	defer restore()
+
+	before := gitCommandCount.Load()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := LoadConfig(b.Context()); err != nil {
+			b.Fatalf("LoadConfig failed: %v", err)
+		}
+	}
+	reportGitCommands(b, before)
+}
+
+func BenchmarkDeleteConfigLookups(b *testing.B) {
+	for _, cached := range []bool{false, true} {
+		b.Run("cached="+strconv.FormatBool(cached), func(b *testing.B) {
+			restore := setupConfigBenchmark(b)
+			defer restore()
+
+			before := gitCommandCount.Load()
+			b.ResetTimer()
+			for range b.N {
+				ctx := b.Context()
+				if cached {
+					ctx = WithConfigCache(ctx)
+				}
+				benchmarkDeleteConfigLookups(ctx, b)
+			}
+			reportGitCommands(b, before)
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Config origins reported by ConfigOrigin in addition to git config scopes
+// (system, global, local, worktree, command).
+const (
+	OriginDefault = "default" // Not configured
+	OriginFile    = "file"    // Repository config file (.gitwt)
+	OriginFlag    = "flag"    // Command-line flag
+)
+
+// ConfigEntry is the effective value of a config key.
+type ConfigEntry struct {
+	Key        string `json:"key"`
+	Value      any    `json:"value"`
+	Origin     string `json:"origin"`
+	Subsection string `json:"subsection,omitempty"` // Branch pattern or profile ("profile.<name>") of the subsection the value is set in
+	Expanded   string `json:"expanded,omitempty"`   // Value after expansion (e.g., resolved basedir path)
+	Note       string `json:"note,omitempty"`       // Additional information (e.g., whether hooks are trusted)
+}
+
+// configKind is the type of the value of a config key.
+type configKind int
+
+const (
+	configKindString configKind = iota
+	configKindBool
+	configKindInt
+	configKindList   // Can be specified multiple times
+	configKindNoCd   // Boolean, "all" or "create" (see wt.nocd)
+	configKindCreate // "auto", "confirm" or "never" (see wt.create)
+	configKindForge  // "github" or "gitlab" (see wt.forge)
+)
+
+// configKinds lists the config keys that can be set, in display order.
+var configKinds = []struct {
+	key  string
+	kind configKind
+}{
+	{configKeyBaseDir, configKindString},
+	{configKeyDirName, configKindString},
+	{configKeyBranchTemplate, configKindString},
+	{configKeyBranchPattern, configKindString},
+	{configKeyCreate, configKindCreate},
+	{configKeyRemote, configKindString},
+	{configKeyStartPoint, configKindString},
+	{configKeyFetch, configKindBool},
+	{configKeyForge, configKindForge},
+	{configKeyCopyIgnored, configKindBool},
+	{configKeyCopyUntracked, configKindBool},
+	{configKeyCopyModified, configKindBool},
+	{configKeyNoCopy, configKindList},
+	{configKeyCopy, configKindList},
+	{configKeyLink, configKindList},
+	{configKeyHardLink, configKindList},
+	{configKeyFollowSymlinks, configKindBool},
+	{configKeyCopyFrom, configKindString},
+	{configKeyTemplate, configKindList},
+	{configKeyCopySource, configKindString},
+	{configKeyPortBlock, configKindInt},
+	{configKeyPortBase, configKindInt},
+	{configKeyHook, configKindList},
+	{configKeyNoCd, configKindNoCd},
+	{configKeyRelative, configKindBool},
+	{configKeyDefaultProfile, configKindString},
+}
+
+// lookupConfigKind returns the kind of a config key, optionally in a branch pattern or profile subsection.
+// Profiles cannot set wt.defaultprofile.
+func lookupConfigKind(name string) (configKind, bool) {
+	subsection, key := splitConfigName(name)
+	if isProfileSubsection(subsection) && key == configKeyDefaultProfile {
+		return 0, false
+	}
+	for _, k := range configKinds {
+		if k.key == key {
+			return k.kind, true
+		}
+	}
+	return 0, false
+}
+
+// IsListConfigKey reports whether key can be specified multiple times.
+func IsListConfigKey(key string) bool {
+	kind, ok := lookupConfigKind(key)
+	return ok && kind == configKindList
+}
+
+// ValidateConfigValue checks that value is valid for key.
+func ValidateConfigValue(name, value string) error {
+	kind, ok := lookupConfigKind(name)
+	if !ok {
+		return unknownConfigKeyError(name)
+	}
+	_, key := splitConfigName(name)
+	switch kind {
+	case configKindBool:
+		if _, err := parseConfigBool(configValue{value: value}); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	case configKindNoCd:
+		if _, err := parseNoCd(configValue{value: value}); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	case configKindCreate:
+		if _, err := parseCreate(configValue{value: value}); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	case configKindForge:
+		if _, err := parseForge(configValue{value: value}); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	case configKindInt:
+		n, err := parseConfigInt(configValue{value: value})
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+		if key == configKeyPortBlock && n < 0 {
+			return fmt.Errorf("invalid %s: %d (must not be negative)", name, n)
+		}
+		if key == configKeyPortBase && (n <= 0 || n > maxPort) {
+			return fmt.Errorf("invalid %s: %d (must be between 1 and %d)", name, n, maxPort)
+		}
+	case configKindList:
+		// An empty value clears the values before it (e.g., to disable hooks for some branches)
+	default:
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("invalid %s: value must not be empty", name)
+		}
+		if key == configKeyBranchTemplate {
+			if _, err := ExpandBranchTemplate(value, "x"); err != nil {
+				return err
+			}
+		}
+		if key == configKeyBranchPattern {
+			if _, err := regexp.Compile(value); err != nil {
+				return fmt.Errorf("invalid %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Entries returns the value of every config key, in display order.
+// Origin and Expanded are not set.
+func (cfg Config) Entries() []ConfigEntry {
+	values := map[string]any{
+		configKeyBaseDir:        cfg.BaseDir,
+		configKeyDirName:        cfg.DirName,
+		configKeyBranchTemplate: cfg.BranchTemplate,
+		configKeyBranchPattern:  cfg.BranchPattern,
+		configKeyCreate:         cfg.Create,
+		configKeyRemote:         cfg.Remote,
+		configKeyStartPoint:     cfg.StartPoint,
+		configKeyFetch:          cfg.Fetch,
+		configKeyForge:          cfg.Forge,
+		configKeyCopyIgnored:    cfg.CopyIgnored,
+		configKeyCopyUntracked:  cfg.CopyUntracked,
+		configKeyCopyModified:   cfg.CopyModified,
+		configKeyNoCopy:         nonNil(cfg.NoCopy),
+		configKeyCopy:           nonNil(cfg.Copy),
+		configKeyLink:           nonNil(cfg.Link),
+		configKeyHardLink:       nonNil(cfg.HardLink),
+		configKeyFollowSymlinks: cfg.FollowSymlinks,
+		configKeyCopyFrom:       cfg.CopyFrom,
+		configKeyTemplate:       nonNil(cfg.Template),
+		configKeyCopySource:     cfg.CopySource,
+		configKeyPortBlock:      cfg.PortBlock,
+		configKeyPortBase:       cfg.PortBase,
+		configKeyHook:           nonNil(cfg.Hooks),
+		configKeyNoCd:           cfg.NoCd,
+		configKeyRelative:       cfg.Relative,
+		configKeyDefaultProfile: cfg.DefaultProfile,
+	}
+	entries := make([]ConfigEntry, 0, len(configKinds))
+	for _, k := range configKinds {
+		entries = append(entries, ConfigEntry{Key: k.key, Value: values[k.key]})
+	}
+	return entries
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// ConfigOrigin returns where the effective value of key comes from: the git config scope
+// (system, global, local, worktree, command), OriginFile for the repository config file,
+// or OriginDefault.
+func ConfigOrigin(ctx context.Context, key string) (string, error) {
+	origin, _, err := ConfigOriginFor(ctx, key, "", "")
+	return origin, err
+}
+
+// ConfigOriginFor returns the origin of the effective value of key for branch and profile like
+// ConfigOrigin, and the subsection it is set in: a branch pattern, "profile.<name>", or empty if
+// it is set outside of subsections. Unlike LoadConfigFor, wt.defaultprofile is not applied.
+func ConfigOriginFor(ctx context.Context, key, branch, profile string) (string, string, error) {
+	snapshot, err := loadConfigSnapshot(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	l := &configLoader{snapshot: snapshot, branch: branch, profile: profile}
+	entries := l.entries(key)
+	if len(entries) == 0 {
+		return OriginDefault, "", nil
+	}
+	last := entries[len(entries)-1]
+	subsection, _ := splitConfigName(last.name)
+	return last.scope, subsection, nil
+}
+
+// SetConfigValues sets key to values in the local (or global) git config.
+// All existing values are replaced. Only list keys accept multiple values.
+func SetConfigValues(ctx context.Context, key string, values []string, global bool) error {
+	if len(values) == 0 {
+		return fmt.Errorf("no value for %s", key)
+	}
+	if len(values) > 1 && !IsListConfigKey(key) {
+		return fmt.Errorf("%s does not accept multiple values", key)
+	}
+	for _, v := range values {
+		if err := ValidateConfigValue(key, v); err != nil {
+			return err
+		}
+	}
+	if err := UnsetConfig(ctx, key, global); err != nil {
+		return err
+	}
+	for _, v := range values {
+		if err := runConfigCommand(ctx, global, "--add", key, v); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// UnsetConfig removes all values of key from the local (or global) git config.
+func UnsetConfig(ctx context.Context, key string, global bool) error {
+	if _, ok := lookupConfigKind(key); !ok {
+		return unknownConfigKeyError(key)
+	}
+	values, err := gitConfigValues(ctx, slices.Concat([]string{"config"}, configScopeArgs(global), []string{"--get-all", key})...)
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	if err := runConfigCommand(ctx, global, "--unset-all", key); err != nil {
+		return fmt.Errorf("failed to unset %s: %w", key, err)
+	}
+	return nil
+}
+
+func unknownConfigKeyError(name string) error {
+	pattern, key := splitConfigName(name)
+	if suggestion := suggestConfigKey(key); suggestion != "" {
+		if pattern != "" {
+			suggestion = "wt." + pattern + "." + strings.TrimPrefix(suggestion, "wt.")
+		}
+		return fmt.Errorf("unknown config key %q (did you mean %q?)", name, suggestion)
+	}
+	return fmt.Errorf("unknown config key %q", name)
+}
+
+func configScopeArgs(global bool) []string {
+	if global {
+		return []string{"--global"}
+	}
+	return []string{"--local"}
+}
+
+func runConfigCommand(ctx context.Context, global bool, args ...string) error {
+	defer invalidateConfigCache(ctx)
+	cmd, err := gitCommand(ctx, slices.Concat([]string{"config"}, configScopeArgs(global), args)...)
+	if err != nil {
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
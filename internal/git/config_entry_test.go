@@ -0,0 +1,167 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestValidateConfigValue(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{"wt.basedir", "../{gitroot}-wt", false},
+		{"wt.basedir", " ", true},
+		{"wt.copyignored", "true", false},
+		{"wt.copyignored", "yes", false},
+		{"wt.copyignored", "maybe", true},
+		{"wt.nocd", "create", false},
+		{"wt.nocd", "sometimes", true},
+		{"wt.portblock", "3", false},
+		{"wt.portblock", "-1", true},
+		{"wt.portbase", "abc", true},
+		{"wt.portbase", "70000", true},
+		{"wt.portbase", "30k", false},
+		{"wt.copy", "*.local", false},
+		{"wt.unknown", "x", true},
+		{"wt.trustedhooks", "x", true},
+		{"wt.release/*.basedir", "~/releases", false},
+		{"wt.release/*.portblock", "-1", true},
+		{"wt.release/*.hook", "", false},
+		{"wt.release/*.nocd", "true", false},
+		{"wt.release/*.trustedhooks", "x", true},
+		{"wt.defaultprofile", "light", false},
+		{"wt.release/*.defaultprofile", "light", false},
+		{"wt.profile.light.copyignored", "false", false},
+		{"wt.profile.light.defaultprofile", "full", true},
+		{"wt.profile.light.nocd", "true", false},
+		{"wt.branchtemplate", "{user}/{input}", false},
+		{"wt.branchtemplate", "{user}/fix", true},
+		{"wt.branchpattern", `^[a-z]+/[0-9]+-`, false},
+		{"wt.branchpattern", `[`, true},
+		{"wt.create", "confirm", false},
+		{"wt.create", "Never", false},
+		{"wt.create", "sometimes", true},
+		{"wt.release/*.create", "auto", false},
+		{"wt.forge", "gitlab", false},
+		{"wt.forge", "bitbucket", true},
+	}
+	for _, tt := range tests {
+		err := ValidateConfigValue(tt.key, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateConfigValue(%q, %q) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfigOrigin(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(RepoConfigFile, "[wt]\n\tnocd = true\n\tcopy = .env\n")
+	repo.Commit("initial commit")
+	repo.Git("config", "wt.copy", "*.local")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"wt.basedir", OriginDefault},
+		{"wt.nocd", OriginFile},
+		{"wt.copy", "local"},
+	}
+	for _, tt := range tests {
+		got, err := ConfigOrigin(t.Context(), tt.key)
+		if err != nil {
+			t.Fatalf("ConfigOrigin(%q) failed: %v", tt.key, err)
+		}
+		if got != tt.want {
+			t.Errorf("ConfigOrigin(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestConfigOriginFor(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(RepoConfigFile, "[wt \"release/*\"]\n\tbasedir = ../releases\n[wt \"profile.light\"]\n\tbasedir = ../light\n")
+	repo.Commit("initial commit")
+	repo.Git("config", "wt.basedir", "../wt")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	tests := []struct {
+		branch         string
+		profile        string
+		wantOrigin     string
+		wantSubsection string
+	}{
+		{"", "", "local", ""},
+		{"feature/x", "", "local", ""},
+		{"release/1.0", "", OriginFile, "release/*"},
+		{"release/1.0", "light", OriginFile, "profile.light"},
+		{"feature/x", "full", "local", ""},
+	}
+	for _, tt := range tests {
+		origin, subsection, err := ConfigOriginFor(t.Context(), "wt.basedir", tt.branch, tt.profile)
+		if err != nil {
+			t.Fatalf("ConfigOriginFor(%q, %q) failed: %v", tt.branch, tt.profile, err)
+		}
+		if origin != tt.wantOrigin || subsection != tt.wantSubsection {
+			t.Errorf("ConfigOriginFor(%q, %q) = %q, %q, want %q, %q", tt.branch, tt.profile, origin, subsection, tt.wantOrigin, tt.wantSubsection)
+		}
+	}
+}
+
+func TestSetConfigValues(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	repo.Git("config", "--add", "wt.copy", "old")
+	repo.Git("config", "--add", "wt.copy", "older")
+
+	// All existing values are replaced
+	if err := SetConfigValues(t.Context(), "wt.copy", []string{".env", "*.local"}, false); err != nil {
+		t.Fatalf("SetConfigValues failed: %v", err)
+	}
+	got, err := GitConfig(t.Context(), "wt.copy")
+	if err != nil {
+		t.Fatalf("GitConfig failed: %v", err)
+	}
+	if want := []string{".env", "*.local"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wt.copy = %v, want %v", got, want)
+	}
+
+	if err := SetConfigValues(t.Context(), "wt.nocd", []string{"true", "false"}, false); err == nil {
+		t.Error("SetConfigValues should fail for multiple values of a single-valued key")
+	}
+	if err := SetConfigValues(t.Context(), "wt.nocd", []string{"maybe"}, false); err == nil {
+		t.Error("SetConfigValues should fail for an invalid bool")
+	}
+
+	if err := UnsetConfig(t.Context(), "wt.copy", false); err != nil {
+		t.Fatalf("UnsetConfig failed: %v", err)
+	}
+	got, err = GitConfig(t.Context(), "wt.copy")
+	if err != nil {
+		t.Fatalf("GitConfig failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("wt.copy = %v after unset, want none", got)
+	}
+
+	// Unsetting a key that is not set is not an error
+	if err := UnsetConfig(t.Context(), "wt.copy", false); err != nil {
+		t.Errorf("UnsetConfig failed for an unset key: %v", err)
+	}
+}
@@ -0,0 +1,310 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// configValue is a raw config value. A key without "=" (e.g., "[wt] copyignored") has no value,
+// which git treats as true.
+type configValue struct {
+	value   string
+	noValue bool
+}
+
+// configLoader parses config values from a snapshot for a branch and profile, collecting warnings about invalid values.
+type configLoader struct {
+	snapshot *configSnapshot
+	branch   string // Branch whose [wt "<pattern>"] subsections apply (empty for none)
+	profile  string // Profile whose [wt "profile.<name>"] subsection applies (empty for none)
+	warnings []string
+}
+
+// entries returns the entries of key that apply to the branch and profile, in precedence order.
+// The profile's entries replace the others when it sets key.
+func (l *configLoader) entries(key string) []configEntry {
+	if l.profile != "" {
+		if entries := l.snapshot.profileEntries(key, l.profile); len(entries) > 0 {
+			return entries
+		}
+	}
+	return l.snapshot.entriesFor(key, l.branch)
+}
+
+// values returns the raw values of key that apply to the branch and profile, in precedence order.
+func (l *configLoader) values(key string) []configValue {
+	var values []configValue
+	for _, e := range l.entries(key) {
+		values = append(values, e.configValue)
+	}
+	return values
+}
+
+// stringValue returns the last value of key, or def if it is not set.
+func (l *configLoader) stringValue(key, def string) string {
+	values := l.values(key)
+	for i := len(values) - 1; i >= 0; i-- {
+		if values[i].noValue || values[i].value == "" {
+			l.warn("invalid %s: missing value (ignored)", key)
+			continue
+		}
+		return values[i].value
+	}
+	return def
+}
+
+// boolValue returns the last valid boolean value of key, or false if it is not set.
+func (l *configLoader) boolValue(key string) bool {
+	values := l.values(key)
+	for i := len(values) - 1; i >= 0; i-- {
+		b, err := parseConfigBool(values[i])
+		if err != nil {
+			l.warn("invalid %s: %v (ignored)", key, err)
+			continue
+		}
+		return b
+	}
+	return false
+}
+
+// noCdValue returns the last valid wt.nocd mode, or NoCdOff if it is not set.
+func (l *configLoader) noCdValue() string {
+	values := l.values(configKeyNoCd)
+	for i := len(values) - 1; i >= 0; i-- {
+		mode, err := parseNoCd(values[i])
+		if err != nil {
+			l.warn("invalid %s: %v (ignored)", configKeyNoCd, err)
+			continue
+		}
+		return mode
+	}
+	return NoCdOff
+}
+
+// createValue returns the last valid wt.create mode, or CreateAuto if it is not set.
+func (l *configLoader) createValue() string {
+	values := l.values(configKeyCreate)
+	for i := len(values) - 1; i >= 0; i-- {
+		mode, err := parseCreate(values[i])
+		if err != nil {
+			l.warn("invalid %s: %v (ignored)", configKeyCreate, err)
+			continue
+		}
+		return mode
+	}
+	return CreateAuto
+}
+
+// forgeValue returns the last valid wt.forge, or ForgeGitHub if it is not set.
+func (l *configLoader) forgeValue() string {
+	values := l.values(configKeyForge)
+	for i := len(values) - 1; i >= 0; i-- {
+		forge, err := parseForge(values[i])
+		if err != nil {
+			l.warn("invalid %s: %v (ignored)", configKeyForge, err)
+			continue
+		}
+		return forge
+	}
+	return ForgeGitHub
+}
+
+// intValue returns the last valid integer value of key, or def if it is not set.
+func (l *configLoader) intValue(key string, def int) int {
+	values := l.values(key)
+	for i := len(values) - 1; i >= 0; i-- {
+		n, err := parseConfigInt(values[i])
+		if err != nil {
+			l.warn("invalid %s: %v (ignored)", key, err)
+			continue
+		}
+		return n
+	}
+	return def
+}
+
+// listValue returns all values of key.
+func (l *configLoader) listValue(key string) []string {
+	return l.nonEmpty(key, l.values(key))
+}
+
+// hookValues returns the hooks from git config and from the repository config file separately.
+func (l *configLoader) hookValues() ([]string, []string) {
+	var repoValues, gitValues []configValue
+	for _, e := range l.entries(configKeyHook) {
+		if e.scope == OriginFile {
+			repoValues = append(repoValues, e.configValue)
+		} else {
+			gitValues = append(gitValues, e.configValue)
+		}
+	}
+	return l.nonEmpty(configKeyHook, gitValues), l.nonEmpty(configKeyHook, repoValues)
+}
+
+// nonEmpty returns the values of a list key. An empty value clears the values before it
+// (e.g., "hook =" in [wt "release/*"] disables hooks for release branches).
+func (l *configLoader) nonEmpty(key string, values []configValue) []string {
+	var result []string
+	for _, v := range values {
+		if v.noValue {
+			l.warn("invalid %s: missing value (ignored)", key)
+			continue
+		}
+		if v.value == "" {
+			result = nil
+			continue
+		}
+		result = append(result, v.value)
+	}
+	return result
+}
+
+// checkUnknownKeys warns about wt.* keys that are not supported, suggesting the closest known key,
+// and about keys ignored in the repository config file.
+func (l *configLoader) checkUnknownKeys() {
+	seen := make(map[string]struct{})
+	for _, e := range l.snapshot.entries {
+		if _, ok := seen[e.name]; ok || isKnownConfigKey(e.name) {
+			continue
+		}
+		seen[e.name] = struct{}{}
+		l.warnings = append(l.warnings, unknownConfigKeyError(e.name).Error())
+	}
+	for _, name := range l.snapshot.ignored {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		l.warn("%s cannot be set in %s (ignored)", name, RepoConfigFile)
+	}
+}
+
+func (l *configLoader) warn(format string, args ...any) {
+	l.warnings = append(l.warnings, fmt.Sprintf(format, args...))
+}
+
+// isKnownConfigKey reports whether name is a supported key, optionally in a branch pattern subsection.
+func isKnownConfigKey(name string) bool {
+	if name == configKeyTrustedHooks {
+		return true
+	}
+	_, ok := lookupConfigKind(name)
+	return ok
+}
+
+// suggestConfigKey returns the known key closest to name, or an empty string if none is close.
+func suggestConfigKey(name string) string {
+	const maxDistance = 2
+	best, bestDistance := "", maxDistance+1
+	for _, k := range configKinds {
+		if d := editDistance(name, k.key); d < bestDistance {
+			best, bestDistance = k.key, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// parseConfigBool parses a boolean with git's rules: a key without value, true, yes, on and
+// non-zero integers are true; false, no, off, 0 and the empty string are false (case-insensitive).
+func parseConfigBool(v configValue) (bool, error) {
+	if v.noValue {
+		return true, nil
+	}
+	switch strings.ToLower(v.value) {
+	case "true", "yes", "on":
+		return true, nil
+	case "false", "no", "off", "":
+		return false, nil
+	}
+	n, err := parseConfigInt(v)
+	if err != nil {
+		return false, fmt.Errorf("bad boolean value %q", v.value)
+	}
+	return n != 0, nil
+}
+
+// parseNoCd parses wt.nocd, which is a boolean, "all" (same as true) or "create"
+// (only for new worktrees), into NoCdOff, NoCdAll or NoCdCreate.
+func parseNoCd(v configValue) (string, error) {
+	switch strings.ToLower(v.value) {
+	case NoCdAll:
+		return NoCdAll, nil
+	case NoCdCreate:
+		return NoCdCreate, nil
+	}
+	b, err := parseConfigBool(v)
+	if err != nil {
+		return "", fmt.Errorf("bad value %q (must be a boolean, all or create)", v.value)
+	}
+	if b {
+		return NoCdAll, nil
+	}
+	return NoCdOff, nil
+}
+
+// parseCreate parses wt.create, which is "auto", "confirm" or "never" (case-insensitive).
+func parseCreate(v configValue) (string, error) {
+	switch mode := strings.ToLower(strings.TrimSpace(v.value)); mode {
+	case CreateAuto, CreateConfirm, CreateNever:
+		return mode, nil
+	}
+	return "", fmt.Errorf("bad value %q (must be auto, confirm or never)", v.value)
+}
+
+// parseForge parses wt.forge, which is "github" or "gitlab" (case-insensitive).
+func parseForge(v configValue) (string, error) {
+	switch forge := strings.ToLower(strings.TrimSpace(v.value)); forge {
+	case ForgeGitHub, ForgeGitLab:
+		return forge, nil
+	}
+	return "", fmt.Errorf("bad value %q (must be github or gitlab)", v.value)
+}
+
+// parseConfigInt parses an integer with git's rules, including the k, m and g unit suffixes.
+func parseConfigInt(v configValue) (int, error) {
+	if v.noValue {
+		return 0, fmt.Errorf("missing value")
+	}
+	s := strings.TrimSpace(v.value)
+	factor := 1
+	if s != "" {
+		switch s[len(s)-1] {
+		case 'k', 'K':
+			factor = 1 << 10
+		case 'm', 'M':
+			factor = 1 << 20
+		case 'g', 'G':
+			factor = 1 << 30
+		}
+		if factor != 1 {
+			s = s[:len(s)-1]
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("bad numeric value %q", v.value)
+	}
+	return n * factor, nil
+}
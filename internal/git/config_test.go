@@ -3,6 +3,8 @@ package git
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/k1LoW/git-wt/testutil"
@@ -235,8 +237,8 @@ func TestLoadConfig(t *testing.T) {
 	if cfg.CopyModified {
 		t.Errorf("LoadConfig().CopyModified default = %v, want false", cfg.CopyModified) //nostyle:errorstrings
 	}
-	if cfg.NoCd {
-		t.Errorf("LoadConfig().NoCd default = %v, want false", cfg.NoCd) //nostyle:errorstrings
+	if cfg.NoCd != NoCdOff {
+		t.Errorf("LoadConfig().NoCd default = %q, want %q", cfg.NoCd, NoCdOff) //nostyle:errorstrings
 	}
 
 	// Test NoCd setting
@@ -247,9 +249,307 @@ func TestLoadConfig(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if !cfg.NoCd {
-		t.Errorf("LoadConfig().NoCd = %v, want true", cfg.NoCd) //nostyle:errorstrings
+	if cfg.NoCd != NoCdAll {
+		t.Errorf("LoadConfig().NoCd = %q, want %q", cfg.NoCd, NoCdAll) //nostyle:errorstrings
 	}
+
+	repo.Git("config", "wt.nocd", "Create")
+	cfg, err = LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.NoCd != NoCdCreate {
+		t.Errorf("LoadConfig().NoCd = %q, want %q", cfg.NoCd, NoCdCreate) //nostyle:errorstrings
+	}
+
+	// Test Create setting
+	if cfg.Create != CreateAuto {
+		t.Errorf("LoadConfig().Create default = %q, want %q", cfg.Create, CreateAuto) //nostyle:errorstrings
+	}
+	repo.Git("config", "wt.create", "Never")
+	repo.Git("config", "--add", "wt.create", "sometimes")
+
+	cfg, err = LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Create != CreateNever {
+		t.Errorf("LoadConfig().Create = %q, want %q (invalid values are ignored)", cfg.Create, CreateNever) //nostyle:errorstrings
+	}
+	repo.Git("config", "--unset-all", "wt.create")
+
+	// Test Link and HardLink patterns
+	repo.Git("config", "--add", "wt.link", "node_modules/")
+	repo.Git("config", "--add", "wt.hardlink", ".venv/")
+	repo.Git("config", "--add", "wt.hardlink", "target/")
+
+	cfg, err = LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(cfg.Link) != 1 || cfg.Link[0] != "node_modules/" {
+		t.Errorf("LoadConfig().Link = %v, want [node_modules/]", cfg.Link) //nostyle:errorstrings
+	}
+	if len(cfg.HardLink) != 2 || cfg.HardLink[0] != ".venv/" || cfg.HardLink[1] != "target/" {
+		t.Errorf("LoadConfig().HardLink = %v, want [.venv/ target/]", cfg.HardLink) //nostyle:errorstrings
+	}
+
+	// Test Template patterns
+	repo.Git("config", "--add", "wt.template", "*.wt.tmpl")
+
+	cfg, err = LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(cfg.Template) != 1 || cfg.Template[0] != "*.wt.tmpl" {
+		t.Errorf("LoadConfig().Template = %v, want [*.wt.tmpl]", cfg.Template) //nostyle:errorstrings
+	}
+
+	// Test PortBlock and PortBase
+	if cfg.PortBlock != 0 || cfg.PortBase != DefaultPortBase {
+		t.Errorf("LoadConfig() PortBlock, PortBase = %d, %d, want 0, %d", cfg.PortBlock, cfg.PortBase, DefaultPortBase) //nostyle:errorstrings
+	}
+	repo.Git("config", "wt.portblock", "3")
+	repo.Git("config", "wt.portbase", "30000")
+
+	cfg, err = LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.PortBlock != 3 || cfg.PortBase != 30000 {
+		t.Errorf("LoadConfig() PortBlock, PortBase = %d, %d, want 3, 30000", cfg.PortBlock, cfg.PortBase) //nostyle:errorstrings
+	}
+
+	// Invalid values are ignored with a warning
+	repo.Git("config", "wt.portblock", "many")
+	cfg, err = LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.PortBlock != 0 {
+		t.Errorf("LoadConfig().PortBlock = %d, want 0 for an invalid value", cfg.PortBlock) //nostyle:errorstrings
+	}
+	if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], "wt.portblock") {
+		t.Errorf("LoadConfig().Warnings = %v, want a warning about wt.portblock", cfg.Warnings) //nostyle:errorstrings
+	}
+}
+
+func TestLoadConfig_GitCompatibleValues(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	repo.Git("config", "wt.copyignored", "yes")
+	repo.Git("config", "wt.copyuntracked", "On")
+	repo.Git("config", "wt.copymodified", "1")
+	repo.Git("config", "wt.relative", "off")
+	repo.Git("config", "wt.portbase", "32k")
+	// A key without value is true
+	f, err := os.OpenFile(filepath.Join(repo.Root, ".git", "config"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("failed to open config: %v", err)
+	}
+	if _, err := f.WriteString("[wt]\n\tnocd\n"); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close config: %v", err)
+	}
+
+	cfg, err := LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.CopyIgnored || !cfg.CopyUntracked || !cfg.CopyModified || cfg.NoCd != NoCdAll {
+		t.Errorf("LoadConfig() CopyIgnored, CopyUntracked, CopyModified, NoCd = %v, %v, %v, %q, want all true", cfg.CopyIgnored, cfg.CopyUntracked, cfg.CopyModified, cfg.NoCd) //nostyle:errorstrings
+	}
+	if cfg.Relative {
+		t.Errorf("LoadConfig().Relative = %v, want false", cfg.Relative) //nostyle:errorstrings
+	}
+	if cfg.PortBase != 32768 {
+		t.Errorf("LoadConfig().PortBase = %d, want 32768", cfg.PortBase) //nostyle:errorstrings
+	}
+	if len(cfg.Warnings) != 0 {
+		t.Errorf("LoadConfig().Warnings = %v, want none", cfg.Warnings) //nostyle:errorstrings
+	}
+
+	// Invalid booleans and unknown keys are reported
+	repo.Git("config", "wt.copyignored", "maybe")
+	repo.Git("config", "wt.copyignore", "true")
+	cfg, err = LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.CopyIgnored {
+		t.Errorf("LoadConfig().CopyIgnored = %v, want false for an invalid value", cfg.CopyIgnored) //nostyle:errorstrings
+	}
+	want := []string{
+		`invalid wt.copyignored: bad boolean value "maybe" (ignored)`,
+		`unknown config key "wt.copyignore" (did you mean "wt.copyignored"?)`,
+	}
+	if !reflect.DeepEqual(cfg.Warnings, want) {
+		t.Errorf("LoadConfig().Warnings = %q, want %q", cfg.Warnings, want) //nostyle:errorstrings
+	}
+}
+
+func TestLoadConfigFor_BranchPattern(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	repo.Git("config", "wt.basedir", "../worktrees")
+	repo.Git("config", "--add", "wt.hook", "npm install")
+	repo.Git("config", "--add", "wt.copy", ".env")
+	repo.Git("config", "wt.release/*.basedir", "~/releases/{gitroot}")
+	repo.Git("config", "wt.release/*.hook", "")
+	repo.Git("config", "wt.release/1.0.basedir", "../legacy")
+	repo.Git("config", "wt.*.copyignored", "true")
+	repo.Git("config", "wt.release/*.copyignore", "true")
+
+	tests := []struct {
+		branch      string
+		wantBaseDir string
+		wantHooks   []string
+		wantIgnored bool
+	}{
+		{"", "../worktrees", []string{"npm install"}, false},
+		{"feature-x", "../worktrees", []string{"npm install"}, true},
+		{"feature/x", "../worktrees", []string{"npm install"}, false},
+		{"release/2.0", "~/releases/{gitroot}", nil, false},
+		{"release/1.0", "../legacy", nil, false},
+		{"release/2.0/hotfix", "../worktrees", []string{"npm install"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			cfg, err := LoadConfigFor(t.Context(), tt.branch, "")
+			if err != nil {
+				t.Fatalf("LoadConfigFor(%q) failed: %v", tt.branch, err)
+			}
+			if cfg.BaseDir != tt.wantBaseDir {
+				t.Errorf("LoadConfigFor(%q).BaseDir = %q, want %q", tt.branch, cfg.BaseDir, tt.wantBaseDir) //nostyle:errorstrings
+			}
+			if !reflect.DeepEqual(cfg.Hooks, tt.wantHooks) {
+				t.Errorf("LoadConfigFor(%q).Hooks = %q, want %q", tt.branch, cfg.Hooks, tt.wantHooks) //nostyle:errorstrings
+			}
+			if cfg.CopyIgnored != tt.wantIgnored {
+				t.Errorf("LoadConfigFor(%q).CopyIgnored = %v, want %v", tt.branch, cfg.CopyIgnored, tt.wantIgnored) //nostyle:errorstrings
+			}
+			// Keys not set in matching subsections keep their global values
+			if !reflect.DeepEqual(cfg.Copy, []string{".env"}) {
+				t.Errorf("LoadConfigFor(%q).Copy = %q, want [.env]", tt.branch, cfg.Copy) //nostyle:errorstrings
+			}
+			want := []string{`unknown config key "wt.release/*.copyignore" (did you mean "wt.release/*.copyignored"?)`}
+			if !reflect.DeepEqual(cfg.Warnings, want) {
+				t.Errorf("LoadConfigFor(%q).Warnings = %q, want %q", tt.branch, cfg.Warnings, want) //nostyle:errorstrings
+			}
+		})
+	}
+}
+
+func TestLoadConfigFor_Profile(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	repo.Git("config", "wt.basedir", "../worktrees")
+	repo.Git("config", "wt.release/*.basedir", "../releases")
+	repo.Git("config", "wt.profile.full.copyignored", "true")
+	repo.Git("config", "wt.profile.full.hook", "npm install")
+	repo.Git("config", "wt.profile.light.nocopy", "*")
+	repo.Git("config", "wt.profile.light.hook", "")
+	repo.Git("config", "wt.profile.light.basedir", "../review")
+
+	tests := []struct {
+		name        string
+		branch      string
+		profile     string
+		wantProfile string
+		wantBaseDir string
+		wantIgnored bool
+		wantHooks   []string
+	}{
+		{"no_profile", "feature", "", "", "../worktrees", false, nil},
+		{"full", "feature", "full", "full", "../worktrees", true, []string{"npm install"}},
+		{"light", "feature", "light", "light", "../review", false, nil},
+		{"profile_over_branch_pattern", "release/1.0", "light", "light", "../review", false, nil},
+		{"branch_pattern_without_profile_value", "release/1.0", "full", "full", "../releases", true, []string{"npm install"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadConfigFor(t.Context(), tt.branch, tt.profile)
+			if err != nil {
+				t.Fatalf("LoadConfigFor(%q, %q) failed: %v", tt.branch, tt.profile, err)
+			}
+			if cfg.Profile != tt.wantProfile {
+				t.Errorf("LoadConfigFor(%q, %q).Profile = %q, want %q", tt.branch, tt.profile, cfg.Profile, tt.wantProfile) //nostyle:errorstrings
+			}
+			if cfg.BaseDir != tt.wantBaseDir {
+				t.Errorf("LoadConfigFor(%q, %q).BaseDir = %q, want %q", tt.branch, tt.profile, cfg.BaseDir, tt.wantBaseDir) //nostyle:errorstrings
+			}
+			if cfg.CopyIgnored != tt.wantIgnored {
+				t.Errorf("LoadConfigFor(%q, %q).CopyIgnored = %v, want %v", tt.branch, tt.profile, cfg.CopyIgnored, tt.wantIgnored) //nostyle:errorstrings
+			}
+			if !reflect.DeepEqual(cfg.Hooks, tt.wantHooks) {
+				t.Errorf("LoadConfigFor(%q, %q).Hooks = %q, want %q", tt.branch, tt.profile, cfg.Hooks, tt.wantHooks) //nostyle:errorstrings
+			}
+		})
+	}
+
+	t.Run("unknown_profile", func(t *testing.T) {
+		if _, err := LoadConfigFor(t.Context(), "", "heavy"); err == nil {
+			t.Error("LoadConfigFor() with an unknown profile should fail")
+		}
+	})
+
+	t.Run("defaultprofile", func(t *testing.T) {
+		repo.Git("config", "wt.defaultprofile", "full")
+		repo.Git("config", "wt.release/*.defaultprofile", "light")
+		t.Cleanup(func() {
+			repo.Git("config", "--unset", "wt.defaultprofile")
+			repo.Git("config", "--unset", "wt.release/*.defaultprofile")
+		})
+		for branch, want := range map[string]string{"feature": "full", "release/1.0": "light"} {
+			cfg, err := LoadConfigFor(t.Context(), branch, "")
+			if err != nil {
+				t.Fatalf("LoadConfigFor(%q) failed: %v", branch, err)
+			}
+			if cfg.Profile != want {
+				t.Errorf("LoadConfigFor(%q).Profile = %q, want %q", branch, cfg.Profile, want) //nostyle:errorstrings
+			}
+		}
+		// --profile wins over wt.defaultprofile
+		cfg, err := LoadConfigFor(t.Context(), "feature", "light")
+		if err != nil {
+			t.Fatalf("LoadConfigFor() failed: %v", err)
+		}
+		if cfg.Profile != "light" {
+			t.Errorf("LoadConfigFor().Profile = %q, want %q", cfg.Profile, "light") //nostyle:errorstrings
+		}
+
+		repo.Git("config", "wt.defaultprofile", "heavy")
+		cfg, err = LoadConfigFor(t.Context(), "feature", "")
+		if err != nil {
+			t.Fatalf("LoadConfigFor() failed: %v", err)
+		}
+		want := []string{`invalid wt.defaultprofile: unknown profile "heavy" (ignored)`}
+		if cfg.Profile != "" || !reflect.DeepEqual(cfg.Warnings, want) {
+			t.Errorf("LoadConfigFor() Profile, Warnings = %q, %q, want \"\", %q", cfg.Profile, cfg.Warnings, want) //nostyle:errorstrings
+		}
+	})
 }
 
 func TestExpandPath(t *testing.T) {
@@ -314,7 +614,7 @@ func TestWorktreePathFor(t *testing.T) {
 	defer restore()
 
 	// Test with default pattern basedir
-	path, err := WorktreePathFor(t.Context(), "../{gitroot}-wt", "feature-branch")
+	path, err := WorktreePathFor(t.Context(), "../{gitroot}-wt", DefaultDirName, "feature-branch")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -326,7 +626,7 @@ func TestWorktreePathFor(t *testing.T) {
 	}
 
 	// Test with custom basedir
-	path, err = WorktreePathFor(t.Context(), "../{gitroot}-worktrees", "feature-branch")
+	path, err = WorktreePathFor(t.Context(), "../{gitroot}-worktrees", DefaultDirName, "feature-branch")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -335,6 +635,17 @@ func TestWorktreePathFor(t *testing.T) {
 	if path != expectedDir {
 		t.Errorf("WorktreePathFor(\"feature-branch\") with custom basedir = %q, want %q", path, expectedDir) //nostyle:errorstrings
 	}
+
+	// Test with dirname template
+	path, err = WorktreePathFor(t.Context(), "../{gitroot}-wt", "{branch_flat}", "feature/login")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedDir = filepath.Clean(filepath.Join(repo.Root, "../repo-wt/feature-login"))
+	if path != expectedDir {
+		t.Errorf("WorktreePathFor(\"feature/login\") with {branch_flat} = %q, want %q", path, expectedDir) //nostyle:errorstrings
+	}
 }
 
 func TestExpandBaseDir(t *testing.T) {
@@ -385,6 +696,56 @@ func TestExpandBaseDir(t *testing.T) {
 	}
 }
 
+func TestExpandBaseDir_Remote(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	// Without remote, the repository is treated like a local remote
+	got, err := ExpandBaseDir(t.Context(), "/wt/{host}/{owner}/{repo}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := filepath.Join("/wt/localhost", filepath.Base(repo.ParentDir()), "repo"); got != want {
+		t.Errorf("ExpandBaseDir() without remote = %q, want %q", got, want) //nostyle:errorstrings
+	}
+
+	repo.Git("remote", "add", "upstream", "https://gitlab.com/group/sub/project.git")
+	got, err = ExpandBaseDir(t.Context(), "/wt/{host}/{owner}/{repo}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "/wt/gitlab.com/group/sub/project"; got != want {
+		t.Errorf("ExpandBaseDir() with first remote = %q, want %q", got, want) //nostyle:errorstrings
+	}
+
+	// origin wins over other remotes
+	repo.Git("remote", "add", "origin", "git@github.com:k1LoW/git-wt.git")
+	got, err = ExpandBaseDir(t.Context(), "/wt/{host}/{owner}/{repo}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "/wt/github.com/k1LoW/git-wt"; got != want {
+		t.Errorf("ExpandBaseDir() with origin = %q, want %q", got, want) //nostyle:errorstrings
+	}
+
+	t.Setenv("GIT_WT_TEST_ROOT", "/srv/wt")
+	got, err = ExpandBaseDir(t.Context(), "{env:GIT_WT_TEST_ROOT}/{repo}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "/srv/wt/git-wt"; got != want {
+		t.Errorf("ExpandBaseDir() with env = %q, want %q", got, want) //nostyle:errorstrings
+	}
+
+	if _, err := ExpandBaseDir(t.Context(), "{env:GIT_WT_TEST_UNSET}/wt"); err == nil {
+		t.Error("ExpandBaseDir() with an unset environment variable should fail")
+	}
+}
+
 func TestIsBaseDirConfigured(t *testing.T) {
 	t.Run("not configured", func(t *testing.T) {
 		repo := testutil.NewTestRepo(t)
@@ -2,68 +2,289 @@ package git
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 )
 
 // CopyOptions holds the copy configuration.
 type CopyOptions struct {
-	CopyIgnored   bool
-	CopyUntracked bool
-	CopyModified  bool
-	NoCopy        []string
-	Copy          []string
-	ExcludeDirs   []string // Directories to exclude from copying (absolute paths)
+	CopyIgnored    bool
+	CopyUntracked  bool
+	CopyModified   bool
+	NoCopy         []string
+	Copy           []string
+	Link           []string      // Patterns for paths to symlink from the source instead of copying
+	HardLink       []string      // Patterns for paths to hardlink from the source instead of copying
+	FollowSymlinks bool          // Copy the targets of symlinks instead of the symlinks themselves
+	Template       []string      // Patterns for template files to render into the worktree
+	CopySource     string        // Directory whose contents are overlaid into the worktree (absolute path)
+	TemplateData   *TemplateData // Data for rendering templates (nil skips rendering)
+	ExcludeDirs    []string      // Directories to exclude from copying (absolute paths)
+	SrcRoot        string        // Root of the worktree to copy from (empty means the current worktree)
+	Log            io.Writer     // Destination for verbose output (nil disables it)
 }
 
+// copyMethod describes how copyFile copied a file.
+type copyMethod string
+
+const (
+	copyMethodClone   copyMethod = "clonefile" // macOS clonefile(2)
+	copyMethodReflink copyMethod = "reflink"   // Linux FICLONE ioctl
+	copyMethodCopy    copyMethod = "copy"      // Regular byte copy
+)
+
+// Copy sources reported in verbose output for each selected file.
+const (
+	sourceCopyIgnored   = "wt.copyignored"
+	sourceCopyUntracked = "wt.copyuntracked"
+	sourceCopyModified  = "wt.copymodified"
+	sourceCopy          = "wt.copy"
+	sourceInclude       = worktreeIncludeFile
+	sourceAttribute     = "wt-copy attribute"
+	sourceCopySource    = "wt.copysource"
+)
+
 // CopyFilesToWorktree copies files to the new worktree based on options.
 func CopyFilesToWorktree(ctx context.Context, srcRoot, dstRoot string, opts CopyOptions) error {
+	plan, err := planCopy(ctx, srcRoot, opts)
+	if err != nil {
+		return err
+	}
+
+	noCopyMatcher := newPatternMatcher(opts.NoCopy)
+	for _, l := range plan.links {
+		if err := linkPath(srcRoot, dstRoot, l, noCopyMatcher, opts); err != nil {
+			// Skip paths that fail to link (e.g., already exist in the worktree)
+			if opts.Log != nil {
+				fmt.Fprintf(opts.Log, "skip %s: %v\n", l.path, err)
+			}
+		}
+	}
+
+	// Copy ignored directories as whole trees, and the remaining files one by one
+	treeFiles := make(map[string][]string)
+	var copied []string
+	for _, file := range plan.files {
+		if dir, ok := containingDir(file, plan.trees); ok {
+			treeFiles[dir] = append(treeFiles[dir], file)
+			continue
+		}
+		copyPath(srcRoot, dstRoot, file, plan.sources[file], opts)
+		copied = append(copied, file)
+	}
+	for _, dir := range plan.trees {
+		copyTree(srcRoot, dstRoot, dir, treeFiles[dir], opts)
+	}
+
+	// Overlay the copy source directory onto the copied files
+	for _, file := range plan.overlay {
+		copyPath(opts.CopySource, dstRoot, file, sourceCopySource, opts)
+		copied = append(copied, file)
+	}
+
+	// Record the copied content so that sync can tell files modified in the worktree.
+	// Files in whole trees are not hashed to keep copying them fast.
+	if err := recordCopies(ctx, dstRoot, copied); err != nil && opts.Log != nil {
+		fmt.Fprintf(opts.Log, "skip recording copied files: %v\n", err)
+	}
+
+	// Render templates last so that rendered files replace copied ones
+	if opts.TemplateData != nil {
+		for _, tmpl := range plan.templates {
+			out, err := renderTemplate(srcRoot, dstRoot, tmpl, opts.TemplateData)
+			if err != nil {
+				return fmt.Errorf("failed to render template %s: %w", tmpl, err)
+			}
+			if opts.Log != nil {
+				fmt.Fprintf(opts.Log, "render %s -> %s\n", tmpl, out)
+			}
+		}
+	}
+
+	return nil
+}
+
+// copyPlan is the set of paths selected from the source worktree by CopyOptions.
+type copyPlan struct {
+	links     []linkEntry // Paths to symlink or hardlink
+	files     []string    // Files to copy (relative paths, ignored directories expanded)
+	trees     []string    // Ignored directories whose files are all in files, copied as a whole
+	templates []string    // Template files to render
+	overlay   []string    // Files to copy from CopySource (relative to CopySource)
+	// sources maps each file in files to the option that selected it (e.g., "wt.copy").
+	sources map[string]string
+}
+
+// planCopy selects the paths to copy or link from srcRoot based on options.
+func planCopy(ctx context.Context, srcRoot string, opts CopyOptions) (copyPlan, error) {
+	plan := copyPlan{sources: make(map[string]string)}
 	var files []string
+	var dirs []string
+
+	// addFiles adds files selected by source. The first source selecting a file is recorded.
+	addFiles := func(selected []string, source string) {
+		for _, file := range selected {
+			if _, exists := plan.sources[file]; !exists {
+				plan.sources[file] = source
+			}
+		}
+		files = append(files, selected...)
+	}
 
 	if opts.CopyIgnored {
-		ignored, err := listIgnoredFiles(ctx, srcRoot)
+		// Whole ignored directories (e.g., node_modules/) are listed as a single entry
+		// and walked as a tree instead of listing every file inside them.
+		ignored, err := listIgnoredEntries(ctx, srcRoot)
 		if err != nil {
-			return err
+			return plan, err
+		}
+		var ignoredFiles []string
+		for _, entry := range ignored {
+			if dir, ok := strings.CutSuffix(entry, "/"); ok {
+				dirs = append(dirs, dir)
+				continue
+			}
+			ignoredFiles = append(ignoredFiles, entry)
 		}
-		files = append(files, ignored...)
+		addFiles(ignoredFiles, sourceCopyIgnored)
 	}
 
 	if opts.CopyUntracked {
 		untracked, err := ListUntrackedFiles(ctx, srcRoot)
 		if err != nil {
-			return err
+			return plan, err
 		}
-		files = append(files, untracked...)
+		addFiles(untracked, sourceCopyUntracked)
 	}
 
 	if opts.CopyModified {
 		modified, err := ListModifiedFiles(ctx, srcRoot)
 		if err != nil {
-			return err
+			return plan, err
+		}
+		addFiles(modified, sourceCopyModified)
+	}
+
+	// Ignored and untracked files for pattern-based selection, listed only when needed
+	var candidates []string
+	listCandidates := func() ([]string, error) {
+		if candidates != nil {
+			return candidates, nil
+		}
+		var err error
+		candidates, err = listCopyCandidates(ctx, srcRoot)
+		if candidates == nil {
+			candidates = []string{}
 		}
-		files = append(files, modified...)
+		return candidates, err
 	}
 
 	// Add files matching Copy patterns (from ignored files)
 	if len(opts.Copy) > 0 {
-		copyFiles, err := listFilesMatchingCopyPatterns(ctx, srcRoot, opts.Copy)
+		all, err := listCandidates()
 		if err != nil {
-			return err
+			return plan, err
 		}
-		files = append(files, copyFiles...)
+		addFiles(matchPatterns(all, opts.Copy), sourceCopy)
+	}
+
+	// Add files selected by the repository: the .worktreeinclude manifest and the wt-copy attribute
+	includePatterns, err := readWorktreeInclude(srcRoot)
+	if err != nil {
+		return plan, err
+	}
+	if len(includePatterns) > 0 {
+		all, err := listCandidates()
+		if err != nil {
+			return plan, err
+		}
+		addFiles(matchPatterns(all, includePatterns), sourceInclude)
+	}
+	usesAttribute, err := usesCopyAttribute(ctx, srcRoot)
+	if err != nil {
+		return plan, err
+	}
+	if usesAttribute {
+		all, err := listCandidates()
+		if err != nil {
+			return plan, err
+		}
+		attributed, err := filterByCopyAttribute(ctx, srcRoot, all)
+		if err != nil {
+			return plan, err
+		}
+		addFiles(attributed, sourceAttribute)
 	}
 
 	// Build NoCopy matcher using gitignore patterns
-	var noCopyMatcher gitignore.Matcher
-	if len(opts.NoCopy) > 0 {
-		var patterns []gitignore.Pattern
-		for _, p := range opts.NoCopy {
-			// Parse pattern from git root (empty domain means root)
-			patterns = append(patterns, gitignore.ParsePattern(p, nil))
+	noCopyMatcher := newPatternMatcher(opts.NoCopy)
+
+	// Template files are rendered instead of copied, and their output replaces copied files
+	replaced := make(map[string]struct{})
+	if len(opts.Template) > 0 {
+		templates, err := listFilesMatchingTemplatePatterns(ctx, srcRoot, opts.Template)
+		if err != nil {
+			return plan, err
+		}
+		for _, tmpl := range templates {
+			if _, exists := replaced[tmpl]; exists {
+				continue
+			}
+			if isExcludedDir(filepath.Join(srcRoot, tmpl), opts.ExcludeDirs) {
+				continue
+			}
+			if noCopyMatcher != nil && noCopyMatcher.Match(splitPath(tmpl), false) {
+				continue
+			}
+			plan.templates = append(plan.templates, tmpl)
+			replaced[tmpl] = struct{}{}
+			replaced[templateOutputPath(tmpl)] = struct{}{}
+		}
+	}
+
+	// Resolve paths to symlink or hardlink instead of copying
+	links, err := listLinkEntries(ctx, srcRoot, opts)
+	if err != nil {
+		return plan, err
+	}
+	for _, l := range links {
+		if isExcludedDir(filepath.Join(srcRoot, l.path), opts.ExcludeDirs) {
+			continue
+		}
+		if noCopyMatcher != nil && noCopyMatcher.Match(splitPath(l.path), l.isDir) {
+			continue
+		}
+		plan.links = append(plan.links, l)
+	}
+
+	// Expand ignored directories into the files inside them. Directories with nothing
+	// filtered out inside them are also copied as whole trees.
+	for _, dir := range dirs {
+		if isExcludedDir(filepath.Join(srcRoot, dir), opts.ExcludeDirs) {
+			continue
+		}
+		if noCopyMatcher != nil && noCopyMatcher.Match(splitPath(dir), true) {
+			continue
+		}
+		if isLinked(dir, links) {
+			continue
+		}
+		files, whole := listDirFiles(srcRoot, dir, noCopyMatcher, links, opts)
+		for _, file := range files {
+			plan.files = append(plan.files, file)
+			plan.sources[file] = sourceCopyIgnored
+		}
+		if whole {
+			plan.trees = append(plan.trees, dir)
 		}
-		noCopyMatcher = gitignore.NewMatcher(patterns)
 	}
 
 	// Remove duplicates
@@ -75,41 +296,527 @@ func CopyFilesToWorktree(ctx context.Context, srcRoot, dstRoot string, opts Copy
 		seen[file] = struct{}{}
 
 		// Skip files inside ExcludeDirs
-		src := filepath.Join(srcRoot, file)
-		shouldSkip := false
-		for _, excludeDir := range opts.ExcludeDirs {
-			// Check if src is inside excludeDir
-			rel, err := filepath.Rel(excludeDir, src)
-			if err == nil && !strings.HasPrefix(rel, "..") {
-				shouldSkip = true
-				break
-			}
-		}
-		if shouldSkip {
+		if isExcludedDir(filepath.Join(srcRoot, file), opts.ExcludeDirs) {
 			continue
 		}
 
 		// Skip files matching NoCopy patterns
 		if noCopyMatcher != nil {
-			// Split file path into components for gitignore matching
-			pathComponents := strings.Split(file, string(filepath.Separator))
 			isDir := false // files from git ls-files are always files
-			if noCopyMatcher.Match(pathComponents, isDir) {
+			if noCopyMatcher.Match(splitPath(file), isDir) {
 				continue
 			}
 		}
 
-		dst := filepath.Join(dstRoot, file)
+		// Skip files already linked (directly or via a linked directory)
+		if isLinked(file, links) {
+			continue
+		}
 
-		if err := copyFile(src, dst); err != nil {
-			// Skip files that fail to copy (e.g., permission issues)
+		// Skip files already listed as part of an ignored directory
+		if isInsideDirs(file, dirs) {
 			continue
 		}
+
+		plan.files = append(plan.files, file)
 	}
 
+	// Files from the copy source directory, filtered by NoCopy patterns
+	if opts.CopySource != "" {
+		overlay, err := listOverlayFiles(opts.CopySource, noCopyMatcher)
+		if err != nil {
+			return plan, err
+		}
+		plan.overlay = overlay
+		// Overlaid files replace files copied from the source worktree
+		for _, file := range overlay {
+			replaced[file] = struct{}{}
+		}
+	}
+
+	// Skip template files, their outputs and overlaid files
+	if len(replaced) > 0 {
+		files := plan.files[:0]
+		for _, file := range plan.files {
+			if _, ok := replaced[file]; !ok {
+				files = append(files, file)
+			}
+		}
+		plan.files = files
+
+		// Directories containing replaced files are copied file by file
+		trees := plan.trees[:0]
+		for _, dir := range plan.trees {
+			if !containsAny(dir, replaced) {
+				trees = append(trees, dir)
+			}
+		}
+		plan.trees = trees
+	}
+
+	return plan, nil
+}
+
+// copyPath copies a single file at rel from srcRoot into dstRoot.
+// source is the option that selected the file, reported in verbose output (empty to omit).
+// Symlinks are preserved unless FollowSymlinks is set.
+// Files that fail to copy (e.g., permission issues) are skipped.
+func copyPath(srcRoot, dstRoot, rel, source string, opts CopyOptions) {
+	src := filepath.Join(srcRoot, rel)
+
+	// Preserve symlinks unless FollowSymlinks is set
+	if !opts.FollowSymlinks {
+		if info, err := os.Lstat(src); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			if err := copySymlink(srcRoot, dstRoot, rel, opts.Log); err != nil && opts.Log != nil {
+				fmt.Fprintf(opts.Log, "skip %s: %v\n", rel, err)
+			}
+			return
+		}
+	}
+
+	method, err := copyFile(src, filepath.Join(dstRoot, rel))
+	if err != nil {
+		if opts.Log != nil {
+			fmt.Fprintf(opts.Log, "skip %s: %v\n", rel, err)
+		}
+		return
+	}
+	if opts.Log != nil && method != "" {
+		if source != "" {
+			fmt.Fprintf(opts.Log, "copy %s (%s, from %s)\n", rel, method, source)
+		} else {
+			fmt.Fprintf(opts.Log, "copy %s (%s)\n", rel, method)
+		}
+	}
+}
+
+// copyTree copies the ignored directory at dir from srcRoot into dstRoot as a whole.
+// The directory is cloned in a single operation where the platform supports it (see cloneDir),
+// otherwise files (the files inside dir) are copied in parallel.
+func copyTree(srcRoot, dstRoot, dir string, files []string, opts CopyOptions) {
+	method, err := cloneDir(filepath.Join(srcRoot, dir), filepath.Join(dstRoot, dir))
+	if err == nil {
+		if opts.Log != nil {
+			fmt.Fprintf(opts.Log, "copy %s/ (%s, from %s)\n", dir, method, sourceCopyIgnored)
+		}
+		return
+	}
+
+	if opts.Log != nil {
+		opts.Log = &lockedWriter{w: opts.Log}
+	}
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for range min(runtime.NumCPU(), len(files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range queue {
+				copyPath(srcRoot, dstRoot, file, sourceCopyIgnored, opts)
+			}
+		}()
+	}
+	for _, file := range files {
+		queue <- file
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// lockedWriter serializes writes from concurrent copies.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// listDirFiles recursively lists the files in the directory at rel in srcRoot.
+// Paths matching NoCopy patterns, linked paths and ExcludeDirs are skipped.
+// whole reports whether nothing was skipped, i.e., the directory can be copied as a whole.
+func listDirFiles(srcRoot, rel string, noCopyMatcher gitignore.Matcher, links []linkEntry, opts CopyOptions) (files []string, whole bool) {
+	whole = true
+	src := filepath.Join(srcRoot, rel)
+	_ = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			whole = false
+			// Skip entries that cannot be read (e.g., permission issues)
+			if opts.Log != nil {
+				fmt.Fprintf(opts.Log, "skip %s: %v\n", path, err)
+			}
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		fileRel, err := filepath.Rel(srcRoot, path)
+		if err != nil {
+			return nil
+		}
+		// Never copy git metadata of nested repositories or worktrees
+		if d.Name() == ".git" {
+			whole = false
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path == src {
+				return nil
+			}
+			if isExcludedDir(path, opts.ExcludeDirs) || isLinked(fileRel, links) ||
+				(noCopyMatcher != nil && noCopyMatcher.Match(splitPath(fileRel), true)) {
+				whole = false
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if (noCopyMatcher != nil && noCopyMatcher.Match(splitPath(fileRel), false)) || isLinked(fileRel, links) {
+			whole = false
+			return nil
+		}
+		// Cloning the directory preserves symlinks, so followed symlinks are copied file by file
+		if opts.FollowSymlinks && d.Type()&fs.ModeSymlink != 0 {
+			whole = false
+		}
+		files = append(files, fileRel)
+		return nil
+	})
+	return files, whole
+}
+
+// listOverlayFiles lists the files in the copy source directory dir, skipping .git and
+// paths matching NoCopy patterns. A missing directory has no files.
+func listOverlayFiles(dir string, noCopyMatcher gitignore.Matcher) ([]string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to stat copy source: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("copy source %s is not a directory", dir)
+	}
+
+	var files []string
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if d.Name() == ".git" {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if noCopyMatcher != nil && noCopyMatcher.Match(splitPath(rel), d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list copy source: %w", err)
+	}
+	return files, nil
+}
+
+// isInsideDirs reports whether file is inside one of dirs (relative paths).
+func isInsideDirs(file string, dirs []string) bool {
+	_, ok := containingDir(file, dirs)
+	return ok
+}
+
+// containingDir returns the one of dirs (relative paths) that file is inside.
+func containingDir(file string, dirs []string) (string, bool) {
+	for _, dir := range dirs {
+		if strings.HasPrefix(file, dir+string(filepath.Separator)) {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+// containsAny reports whether one of paths (relative paths) is inside dir.
+func containsAny(dir string, paths map[string]struct{}) bool {
+	for path := range paths {
+		if strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// linkKind describes how a path is linked into the new worktree.
+type linkKind int
+
+const (
+	linkSymlink linkKind = iota
+	linkHardlink
+)
+
+// linkEntry is a file or directory to link instead of copying.
+type linkEntry struct {
+	path  string // Path relative to the source root
+	isDir bool
+	kind  linkKind
+}
+
+// listLinkEntries returns the ignored and untracked paths matching Link or HardLink patterns.
+// When a directory matches, the whole directory is returned as a single entry.
+// Symlink patterns take precedence over hardlink patterns.
+func listLinkEntries(ctx context.Context, srcRoot string, opts CopyOptions) ([]linkEntry, error) {
+	linkMatcher := newPatternMatcher(opts.Link)
+	hardLinkMatcher := newPatternMatcher(opts.HardLink)
+	if linkMatcher == nil && hardLinkMatcher == nil {
+		return nil, nil
+	}
+
+	ignored, err := listIgnoredFiles(ctx, srcRoot)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := ListUntrackedFiles(ctx, srcRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []linkEntry
+	seen := make(map[string]struct{})
+	for _, file := range append(ignored, untracked...) {
+		components := splitPath(file)
+		// Check parent directories first so the outermost matching directory is linked as a whole
+		for i := 1; i <= len(components); i++ {
+			isDir := i < len(components)
+			kind, ok := matchLinkKind(linkMatcher, hardLinkMatcher, components[:i], isDir)
+			if !ok {
+				continue
+			}
+			path := filepath.Join(components[:i]...)
+			if _, exists := seen[path]; !exists {
+				seen[path] = struct{}{}
+				entries = append(entries, linkEntry{path: path, isDir: isDir, kind: kind})
+			}
+			break
+		}
+	}
+
+	return entries, nil
+}
+
+// matchLinkKind reports whether the path matches a Link or HardLink pattern, and which one.
+func matchLinkKind(linkMatcher, hardLinkMatcher gitignore.Matcher, components []string, isDir bool) (linkKind, bool) {
+	if linkMatcher != nil && linkMatcher.Match(components, isDir) {
+		return linkSymlink, true
+	}
+	if hardLinkMatcher != nil && hardLinkMatcher.Match(components, isDir) {
+		return linkHardlink, true
+	}
+	return 0, false
+}
+
+// linkPath links a single entry from srcRoot into dstRoot.
+func linkPath(srcRoot, dstRoot string, l linkEntry, noCopyMatcher gitignore.Matcher, opts CopyOptions) error {
+	src := filepath.Join(srcRoot, l.path)
+	dst := filepath.Join(dstRoot, l.path)
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	if l.kind == linkSymlink {
+		if err := os.Symlink(src, dst); err != nil {
+			return err
+		}
+		if opts.Log != nil {
+			fmt.Fprintf(opts.Log, "symlink %s\n", l.path)
+		}
+		return nil
+	}
+
+	if !l.isDir {
+		return hardLinkFile(src, dst, l.path, opts.Log)
+	}
+
+	// Directories cannot be hardlinked, so hardlink every file inside instead
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcRoot, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != src && isExcludedDir(path, opts.ExcludeDirs) {
+				return filepath.SkipDir
+			}
+			if noCopyMatcher != nil && noCopyMatcher.Match(splitPath(rel), true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if noCopyMatcher != nil && noCopyMatcher.Match(splitPath(rel), false) {
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink != 0 && !opts.FollowSymlinks {
+			if err := copySymlink(srcRoot, dstRoot, rel, opts.Log); err != nil && opts.Log != nil {
+				fmt.Fprintf(opts.Log, "skip %s: %v\n", rel, err)
+			}
+			return nil
+		}
+		if err := hardLinkFile(path, filepath.Join(dstRoot, rel), rel, opts.Log); err != nil && opts.Log != nil {
+			fmt.Fprintf(opts.Log, "skip %s: %v\n", rel, err)
+		}
+		return nil
+	})
+}
+
+// hardLinkFile hardlinks src to dst, falling back to a copy when hardlinking is not possible
+// (e.g., across filesystems).
+func hardLinkFile(src, dst, rel string, log io.Writer) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Link(src, dst); err == nil {
+		if log != nil {
+			fmt.Fprintf(log, "hardlink %s\n", rel)
+		}
+		return nil
+	}
+	method, err := copyFile(src, dst)
+	if err != nil {
+		return err
+	}
+	if log != nil && method != "" {
+		fmt.Fprintf(log, "copy %s (%s, hardlink failed)\n", rel, method)
+	}
 	return nil
 }
 
+// copySymlink recreates the symlink at rel in dstRoot instead of copying its target.
+// Targets that resolve inside srcRoot are rewritten as relative links so they resolve
+// inside dstRoot, and other relative targets are made absolute so they keep pointing
+// at the same location. Dangling symlinks are copied as is.
+func copySymlink(srcRoot, dstRoot, rel string, log io.Writer) error {
+	src := filepath.Join(srcRoot, rel)
+	dst := filepath.Join(dstRoot, rel)
+
+	target, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+
+	resolved := target
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(filepath.Dir(src), resolved)
+	}
+	resolved = filepath.Clean(resolved)
+
+	newTarget := resolved
+	if inside, ok := relativeWithin(srcRoot, resolved); ok {
+		newTarget, err = filepath.Rel(filepath.Dir(dst), filepath.Join(dstRoot, inside))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Symlink(newTarget, dst); err != nil {
+		return err
+	}
+	if log != nil {
+		fmt.Fprintf(log, "copy %s (symlink -> %s)\n", rel, newTarget)
+	}
+	return nil
+}
+
+// relativeWithin returns path relative to root and reports whether path is inside root.
+func relativeWithin(root, path string) (string, bool) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// isLinked reports whether file is one of the linked entries or inside a linked directory.
+func isLinked(file string, links []linkEntry) bool {
+	for _, l := range links {
+		if file == l.path || (l.isDir && strings.HasPrefix(file, l.path+string(filepath.Separator))) {
+			return true
+		}
+	}
+	return false
+}
+
+// isExcludedDir reports whether path is inside one of the excluded directories.
+func isExcludedDir(path string, excludeDirs []string) bool {
+	for _, excludeDir := range excludeDirs {
+		// Check if path is inside excludeDir
+		rel, err := filepath.Rel(excludeDir, path)
+		if err == nil && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
+}
+
+// newPatternMatcher builds a gitignore matcher from patterns.
+// It returns nil when there are no patterns.
+func newPatternMatcher(patterns []string) gitignore.Matcher {
+	if len(patterns) == 0 {
+		return nil
+	}
+	var ps []gitignore.Pattern
+	for _, p := range patterns {
+		// Parse pattern from git root (empty domain means root)
+		ps = append(ps, gitignore.ParsePattern(p, nil))
+	}
+	return gitignore.NewMatcher(ps)
+}
+
+// splitPath splits a relative file path into components for gitignore matching.
+func splitPath(path string) []string {
+	return strings.Split(path, string(filepath.Separator))
+}
+
+// listIgnoredEntries returns files and directories ignored by .gitignore.
+// Unlike listIgnoredFiles, a directory whose contents are all ignored is returned
+// as a single entry with a trailing slash instead of every file inside it.
+func listIgnoredEntries(ctx context.Context, root string) ([]string, error) {
+	cmd, err := gitCommand(ctx, "ls-files", "--others", "--ignored", "--exclude-standard", "--directory")
+	if err != nil {
+		return nil, err
+	}
+	cmd.Dir = root
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseFileList(string(out)), nil
+}
+
 // listIgnoredFiles returns files ignored by .gitignore.
 func listIgnoredFiles(ctx context.Context, root string) ([]string, error) {
 	cmd, err := gitCommand(ctx, "ls-files", "--others", "--ignored", "--exclude-standard")
@@ -170,6 +877,16 @@ func parseFileList(out string) []string {
 
 // listFilesMatchingCopyPatterns returns ignored and untracked files that match the given patterns.
 func listFilesMatchingCopyPatterns(ctx context.Context, root string, patterns []string) ([]string, error) {
+	candidates, err := listCopyCandidates(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	return matchPatterns(candidates, patterns), nil
+}
+
+// listCopyCandidates returns the ignored and untracked files that pattern-based
+// selection (wt.copy, .worktreeinclude, wt-copy attribute) chooses from.
+func listCopyCandidates(ctx context.Context, root string) ([]string, error) {
 	// Get ignored files
 	ignored, err := listIgnoredFiles(ctx, root)
 	if err != nil {
@@ -183,24 +900,20 @@ func listFilesMatchingCopyPatterns(ctx context.Context, root string, patterns []
 	}
 
 	// Combine both lists
-	allFiles := append(ignored, untracked...)
+	return append(ignored, untracked...), nil
+}
 
-	// Build matcher from patterns
-	var matcherPatterns []gitignore.Pattern
-	for _, p := range patterns {
-		matcherPatterns = append(matcherPatterns, gitignore.ParsePattern(p, nil))
+// matchPatterns returns the files matching the gitignore patterns.
+func matchPatterns(files, patterns []string) []string {
+	matcher := newPatternMatcher(patterns)
+	if matcher == nil {
+		return nil
 	}
-	matcher := gitignore.NewMatcher(matcherPatterns)
-
-	// Filter files matching patterns
 	var result []string
-	for _, file := range allFiles {
-		pathComponents := strings.Split(file, string(filepath.Separator))
-		if matcher.Match(pathComponents, false) {
+	for _, file := range files {
+		if matcher.Match(splitPath(file), false) {
 			result = append(result, file)
 		}
 	}
-
-	return result, nil
+	return result
 }
-
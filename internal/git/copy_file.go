@@ -1,55 +1,59 @@
-//go:build !darwin
+//go:build !darwin && !linux
 
-// Default implementation for non-Darwin platforms.
-// On Linux (kernel 4.5+), io.Copy internally attempts copy_file_range(2),
-// which enables efficient in-kernel copying on supported filesystems
-// (Btrfs, XFS, ext4, NFS 4.2+, etc.) without data passing through userspace.
+// Default implementation for platforms without a copy-on-write clone API.
+// Files are copied with io.Copy.
 
 package git
 
 import (
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
 )
 
-func copyFile(src, dst string) error {
+func copyFile(src, dst string) (copyMethod, error) {
 	srcInfo, err := os.Stat(src)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Skip directories
 	if srcInfo.IsDir() {
-		return nil
+		return "", nil
 	}
 
 	// Create parent directory
 	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
-		return err
+		return "", err
 	}
 
 	in, err := os.Open(src)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer in.Close()
 
 	out, err := os.Create(dst)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer out.Close()
 
 	if _, err := io.Copy(out, in); err != nil {
-		return err
+		return "", err
 	}
 
 	// Preserve file permissions
 	if err := os.Chmod(dst, srcInfo.Mode()); err != nil {
-		return err
+		return "", err
 	}
 
 	// Preserve file timestamps
-	return os.Chtimes(dst, srcInfo.ModTime(), srcInfo.ModTime())
+	return copyMethodCopy, os.Chtimes(dst, srcInfo.ModTime(), srcInfo.ModTime())
+}
+
+// cloneDir is not supported on this platform.
+func cloneDir(_, _ string) (copyMethod, error) {
+	return "", errors.ErrUnsupported
 }
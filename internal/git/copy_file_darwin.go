@@ -15,31 +15,43 @@ import (
 	"golang.org/x/sys/unix"
 )
 
-func copyFile(src, dst string) error {
+func copyFile(src, dst string) (copyMethod, error) {
 	srcInfo, err := os.Stat(src)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Skip directories
 	if srcInfo.IsDir() {
-		return nil
+		return "", nil
 	}
 
 	// Create parent directory
 	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
-		return err
+		return "", err
 	}
 
 	// Try clonefile first (APFS Copy-on-Write)
 	if err := unix.Clonefile(src, dst, unix.CLONE_NOFOLLOW); err == nil {
 		// clonefile preserves most permissions but strips setuid/setgid bits,
 		// so chmod is needed to restore the original mode completely.
-		return os.Chmod(dst, srcInfo.Mode())
+		return copyMethodClone, os.Chmod(dst, srcInfo.Mode())
 	}
 
 	// Fallback to traditional copy (non-APFS, cross-device, etc.)
-	return copyFileTraditional(src, dst, srcInfo)
+	return copyMethodCopy, copyFileTraditional(src, dst, srcInfo)
+}
+
+// cloneDir clones the directory tree at src to dst with a single clonefile call.
+// It fails when dst exists or the filesystem does not support cloning.
+func cloneDir(src, dst string) (copyMethod, error) {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", err
+	}
+	if err := unix.Clonefile(src, dst, unix.CLONE_NOFOLLOW); err != nil {
+		return "", err
+	}
+	return copyMethodClone, nil
 }
 
 func copyFileTraditional(src, dst string, srcInfo os.FileInfo) error {
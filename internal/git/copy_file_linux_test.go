@@ -0,0 +1,82 @@
+//go:build linux
+
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyFile_Linux(t *testing.T) {
+	tmpDir := t.TempDir()
+	srcPath := filepath.Join(tmpDir, "src.txt")
+	dstPath := filepath.Join(tmpDir, "dst.txt")
+
+	if err := os.WriteFile(srcPath, []byte("content"), 0600); err != nil {
+		t.Fatalf("failed to create source file: %v", err)
+	}
+
+	method, err := copyFile(srcPath, dstPath)
+	if err != nil {
+		t.Fatalf("copyFile failed: %v", err)
+	}
+	// The method depends on the filesystem backing the temp dir
+	if method != copyMethodReflink && method != copyMethodCopy {
+		t.Errorf("copyFile() method = %q, want %q or %q", method, copyMethodReflink, copyMethodCopy)
+	}
+
+	content, err := os.ReadFile(dstPath)
+	if err != nil {
+		t.Fatalf("failed to read destination file: %v", err)
+	}
+	if string(content) != "content" {
+		t.Errorf("destination content = %q, want %q", string(content), "content")
+	}
+}
+
+func TestCopyFile_Linux_FallbackOnTmpfs(t *testing.T) {
+	// tmpfs does not support FICLONE, so copyFile must fall back to a regular copy
+	const shm = "/dev/shm"
+	if info, err := os.Stat(shm); err != nil || !info.IsDir() {
+		t.Skipf("%s is not available", shm)
+	}
+	tmpDir, err := os.MkdirTemp(shm, "git-wt-test-*")
+	if err != nil {
+		t.Skipf("failed to create temp dir in %s: %v", shm, err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	srcPath := filepath.Join(tmpDir, "src.txt")
+	dstPath := filepath.Join(tmpDir, "nested", "dst.txt")
+
+	if err := os.WriteFile(srcPath, []byte("fallback content"), 0640); err != nil {
+		t.Fatalf("failed to create source file: %v", err)
+	}
+
+	method, err := copyFile(srcPath, dstPath)
+	if err != nil {
+		t.Fatalf("copyFile failed: %v", err)
+	}
+	if method != copyMethodCopy {
+		t.Errorf("copyFile() method = %q, want %q", method, copyMethodCopy)
+	}
+
+	content, err := os.ReadFile(dstPath)
+	if err != nil {
+		t.Fatalf("failed to read destination file: %v", err)
+	}
+	if string(content) != "fallback content" {
+		t.Errorf("destination content = %q, want %q", string(content), "fallback content")
+	}
+
+	info, err := os.Stat(dstPath)
+	if err != nil {
+		t.Fatalf("failed to stat destination file: %v", err)
+	}
+	if info.Mode().Perm() != 0640 {
+		t.Errorf("destination mode = %v, want %v", info.Mode().Perm(), os.FileMode(0640))
+	}
+}
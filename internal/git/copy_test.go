@@ -3,6 +3,8 @@ package git
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"testing"
 	"time"
 
@@ -542,7 +544,7 @@ func TestCopyFile_PreservesTimestamps(t *testing.T) {
 		t.Fatalf("failed to set source file time: %v", err)
 	}
 
-	if err := copyFile(srcPath, dstPath); err != nil {
+	if _, err := copyFile(srcPath, dstPath); err != nil {
 		t.Fatalf("copyFile failed: %v", err)
 	}
 
@@ -603,3 +605,530 @@ func TestCopyFilesToWorktree_ExcludeDirs(t *testing.T) {
 		t.Error(".worktrees/.gitignore should NOT have been copied")
 	}
 }
+
+func TestCopyFilesToWorktree_Link(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", "node_modules/\n.env\n*.cache\n")
+	repo.Commit("initial commit")
+
+	// Create ignored files
+	repo.CreateFile("node_modules/foo/index.js", "module.exports = {}")
+	repo.CreateFile("node_modules/bar/index.js", "module.exports = {}")
+	repo.CreateFile("build.cache", "cache")
+	repo.CreateFile(".env", "SECRET=value")
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	opts := CopyOptions{
+		CopyIgnored: true,
+		Link:        []string{"node_modules/", "*.cache"},
+	}
+	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts)
+	if err != nil {
+		t.Fatalf("CopyFilesToWorktree failed: %v", err)
+	}
+
+	// node_modules should be a single symlink to the source directory
+	target, err := os.Readlink(filepath.Join(dstDir, "node_modules"))
+	if err != nil {
+		t.Fatalf("node_modules should be a symlink: %v", err)
+	}
+	if target != repo.Path("node_modules") {
+		t.Errorf("node_modules symlink target = %q, want %q", target, repo.Path("node_modules"))
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, "node_modules/foo/index.js")); err != nil {
+		t.Errorf("node_modules/foo/index.js should be reachable through the symlink: %v", err)
+	}
+
+	// build.cache should be a symlink to the source file
+	info, err := os.Lstat(filepath.Join(dstDir, "build.cache"))
+	if err != nil {
+		t.Fatalf("build.cache should exist: %v", err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Error("build.cache should be a symlink")
+	}
+
+	// .env should be an independent copy
+	info, err = os.Lstat(filepath.Join(dstDir, ".env"))
+	if err != nil {
+		t.Fatalf(".env should have been copied: %v", err)
+	}
+	if !info.Mode().IsRegular() {
+		t.Error(".env should be a regular file")
+	}
+}
+
+func TestCopyFilesToWorktree_Link_WithoutCopyIgnored(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", ".venv/\n.env\n")
+	repo.Commit("initial commit")
+
+	repo.CreateFile(".venv/bin/python", "#!/bin/sh")
+	repo.CreateFile(".env", "SECRET=value")
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	// Link patterns select paths on their own, like Copy patterns
+	opts := CopyOptions{
+		Link: []string{".venv/"},
+	}
+	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts)
+	if err != nil {
+		t.Fatalf("CopyFilesToWorktree failed: %v", err)
+	}
+
+	if _, err := os.Readlink(filepath.Join(dstDir, ".venv")); err != nil {
+		t.Errorf(".venv should be a symlink: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(dstDir, ".env")); !os.IsNotExist(err) {
+		t.Error(".env should NOT have been copied")
+	}
+}
+
+func TestCopyFilesToWorktree_HardLink(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", "target/\n")
+	repo.Commit("initial commit")
+
+	repo.CreateFile("target/debug/app", "binary")
+	repo.CreateFile("target/debug/app.log", "log")
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	opts := CopyOptions{
+		HardLink: []string{"target/"},
+		NoCopy:   []string{"*.log"},
+	}
+	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts)
+	if err != nil {
+		t.Fatalf("CopyFilesToWorktree failed: %v", err)
+	}
+
+	// target should be a real directory with hardlinked files
+	info, err := os.Lstat(filepath.Join(dstDir, "target"))
+	if err != nil {
+		t.Fatalf("target should exist: %v", err)
+	}
+	if !info.IsDir() {
+		t.Error("target should be a directory, not a symlink")
+	}
+
+	srcInfo, err := os.Stat(repo.Path("target/debug/app"))
+	if err != nil {
+		t.Fatalf("failed to stat source file: %v", err)
+	}
+	dstInfo, err := os.Stat(filepath.Join(dstDir, "target/debug/app"))
+	if err != nil {
+		t.Fatalf("target/debug/app should have been hardlinked: %v", err)
+	}
+	if !os.SameFile(srcInfo, dstInfo) {
+		t.Error("target/debug/app should be a hardlink to the source file")
+	}
+
+	// NoCopy patterns apply inside hardlinked directories
+	if _, err := os.Stat(filepath.Join(dstDir, "target/debug/app.log")); !os.IsNotExist(err) {
+		t.Error("target/debug/app.log should NOT have been linked (NoCopy takes precedence)")
+	}
+}
+
+func TestCopyFilesToWorktree_Link_Precedence(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", "cache/\nvendor/\n")
+	repo.Commit("initial commit")
+
+	repo.CreateFile("cache/data.bin", "data")
+	repo.CreateFile("vendor/lib.go", "package lib")
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	opts := CopyOptions{
+		CopyIgnored: true,
+		Link:        []string{"cache/", "vendor/"},
+		HardLink:    []string{"cache/"},
+		NoCopy:      []string{"vendor/"},
+	}
+	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts)
+	if err != nil {
+		t.Fatalf("CopyFilesToWorktree failed: %v", err)
+	}
+
+	// Link takes precedence over HardLink
+	if _, err := os.Readlink(filepath.Join(dstDir, "cache")); err != nil {
+		t.Errorf("cache should be a symlink (Link takes precedence over HardLink): %v", err)
+	}
+
+	// NoCopy takes precedence over Link
+	if _, err := os.Lstat(filepath.Join(dstDir, "vendor")); !os.IsNotExist(err) {
+		t.Error("vendor should NOT have been linked (NoCopy takes precedence)")
+	}
+}
+
+func TestCopyFilesToWorktree_Symlinks(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", ".env\nconfig\ndangling\nabs-link\n")
+	repo.Commit("initial commit")
+
+	// Files referenced by symlinks
+	secretsDir := filepath.Join(repo.ParentDir(), "secrets")
+	if err := os.MkdirAll(secretsDir, 0755); err != nil {
+		t.Fatalf("failed to create secrets dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(secretsDir, ".env"), []byte("SECRET=value"), 0600); err != nil {
+		t.Fatalf("failed to create secrets file: %v", err)
+	}
+	repo.CreateFile("shared/config/app.yml", "app: true")
+
+	// Relative symlink pointing outside the source root
+	if err := os.Symlink("../secrets/.env", repo.Path(".env")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	// Relative symlink to a directory inside the source root
+	if err := os.Symlink("shared/config", repo.Path("config")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	// Dangling symlink
+	if err := os.Symlink("does-not-exist", repo.Path("dangling")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	// Absolute symlink pointing inside the source root
+	if err := os.Symlink(repo.Path("README.md"), repo.Path("abs-link")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	opts := CopyOptions{CopyIgnored: true}
+	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts)
+	if err != nil {
+		t.Fatalf("CopyFilesToWorktree failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		// Relative targets outside the source root are made absolute
+		{".env", filepath.Join(secretsDir, ".env")},
+		// Relative targets inside the source root are kept relative
+		{"config", "shared/config"},
+		{"dangling", "does-not-exist"},
+		// Absolute targets inside the source root are rewritten to resolve inside the new worktree
+		{"abs-link", "README.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := os.Readlink(filepath.Join(dstDir, tt.name))
+			if err != nil {
+				t.Fatalf("%s should be a symlink: %v", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("%s symlink target = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyFilesToWorktree_FollowSymlinks(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", ".env\nconfig\n")
+	repo.Commit("initial commit")
+
+	repo.CreateFile("shared/.env", "SECRET=value")
+	repo.CreateFile("shared/config/app.yml", "app: true")
+	if err := os.Symlink("shared/.env", repo.Path(".env")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	if err := os.Symlink("shared/config", repo.Path("config")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	opts := CopyOptions{CopyIgnored: true, FollowSymlinks: true}
+	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts)
+	if err != nil {
+		t.Fatalf("CopyFilesToWorktree failed: %v", err)
+	}
+
+	// .env should be dereferenced and copied as a regular file
+	info, err := os.Lstat(filepath.Join(dstDir, ".env"))
+	if err != nil {
+		t.Fatalf(".env should have been copied: %v", err)
+	}
+	if !info.Mode().IsRegular() {
+		t.Error(".env should be a regular file")
+	}
+	content, err := os.ReadFile(filepath.Join(dstDir, ".env"))
+	if err != nil {
+		t.Fatalf("failed to read .env: %v", err)
+	}
+	if string(content) != "SECRET=value" {
+		t.Errorf(".env content = %q, want %q", string(content), "SECRET=value")
+	}
+
+	// Symlinks to directories are skipped when followed (directories are not copied)
+	if _, err := os.Lstat(filepath.Join(dstDir, "config")); !os.IsNotExist(err) {
+		t.Error("config should NOT have been copied")
+	}
+}
+
+func TestListIgnoredEntries(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", ".env\nnode_modules/\n")
+	repo.Commit("initial commit")
+
+	repo.CreateFile(".env", "SECRET=value")
+	repo.CreateFile("node_modules/a/index.js", "a")
+	repo.CreateFile("node_modules/b/index.js", "b")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	got, err := listIgnoredEntries(t.Context(), repo.Root)
+	if err != nil {
+		t.Fatalf("listIgnoredEntries failed: %v", err)
+	}
+	want := []string{".env", "node_modules/"}
+	if len(got) != len(want) {
+		t.Fatalf("listIgnoredEntries() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("listIgnoredEntries()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCopyFilesToWorktree_IgnoredDirectory(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", "node_modules/\ntarget/\n")
+	repo.Commit("initial commit")
+
+	// Create ignored directory trees
+	repo.CreateFile("node_modules/pkg/index.js", "module.exports = {}")
+	repo.CreateFile("node_modules/pkg/index.js.map", "{}")
+	repo.CreateFile("node_modules/pkg/lib/deep/util.js", "util")
+	repo.CreateFile("node_modules/.cache/data", "cache")
+	repo.CreateFile("target/debug/app", "binary")
+	repo.CreateFile("target/wt/existing/README.md", "# Existing worktree")
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	opts := CopyOptions{
+		CopyIgnored: true,
+		NoCopy:      []string{"*.map", "node_modules/.cache/"},
+		ExcludeDirs: []string{repo.Path("target/wt")},
+	}
+	err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts)
+	if err != nil {
+		t.Fatalf("CopyFilesToWorktree failed: %v", err)
+	}
+
+	// Files inside ignored directories should be copied
+	for _, file := range []string{"node_modules/pkg/index.js", "node_modules/pkg/lib/deep/util.js", "target/debug/app"} {
+		if _, err := os.Stat(filepath.Join(dstDir, file)); err != nil {
+			t.Errorf("%s should have been copied: %v", file, err)
+		}
+	}
+
+	// NoCopy patterns apply inside ignored directories
+	for _, file := range []string{"node_modules/pkg/index.js.map", "node_modules/.cache/data"} {
+		if _, err := os.Stat(filepath.Join(dstDir, file)); !os.IsNotExist(err) {
+			t.Errorf("%s should NOT have been copied (matches NoCopy)", file)
+		}
+	}
+
+	// ExcludeDirs apply inside ignored directories
+	if _, err := os.Stat(filepath.Join(dstDir, "target/wt")); !os.IsNotExist(err) {
+		t.Error("target/wt should NOT have been copied (in ExcludeDirs)")
+	}
+}
+
+func TestPlanCopy_Trees(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", "node_modules/\ntarget/\nvendor/\n")
+	repo.Commit("initial commit")
+
+	repo.CreateFile("node_modules/pkg/index.js", "module.exports = {}")
+	repo.CreateFile("target/debug/app", "binary")
+	repo.CreateFile("target/debug/app.log", "log")
+	repo.CreateFile("vendor/lib/config.tmpl", "{{.Branch}}")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	plan, err := planCopy(t.Context(), repo.Root, CopyOptions{
+		CopyIgnored: true,
+		NoCopy:      []string{"*.log"},
+		Template:    []string{"*.tmpl"},
+	})
+	if err != nil {
+		t.Fatalf("planCopy failed: %v", err)
+	}
+
+	// Only directories with nothing filtered out inside them are copied as whole trees
+	if want := []string{"node_modules"}; !slices.Equal(plan.trees, want) {
+		t.Errorf("trees = %v, want %v", plan.trees, want)
+	}
+	for _, file := range []string{"node_modules/pkg/index.js", "target/debug/app"} {
+		if !slices.Contains(plan.files, filepath.FromSlash(file)) {
+			t.Errorf("files should contain %s, got %v", file, plan.files)
+		}
+	}
+	if slices.Contains(plan.files, filepath.FromSlash("target/debug/app.log")) {
+		t.Errorf("files should not contain target/debug/app.log (matches NoCopy), got %v", plan.files)
+	}
+}
+
+func BenchmarkCopyFilesToWorktree_IgnoredDirectory(b *testing.B) {
+	repo := testutil.NewTestRepo(b)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", "node_modules/\n")
+	repo.Commit("initial commit")
+
+	// Simulate a JavaScript dependency tree
+	for i := range 50 {
+		for j := range 20 {
+			repo.CreateFile(filepath.Join("node_modules", "pkg"+strconv.Itoa(i), "lib", strconv.Itoa(j)+".js"), "module.exports = {}")
+		}
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	b.ResetTimer()
+	for i := range b.N {
+		dstDir := filepath.Join(repo.ParentDir(), "dst"+strconv.Itoa(i))
+		if err := CopyFilesToWorktree(b.Context(), repo.Root, dstDir, CopyOptions{CopyIgnored: true}); err != nil {
+			b.Fatalf("CopyFilesToWorktree failed: %v", err)
+		}
+	}
+}
+
+func TestCopyFilesToWorktree_CopySource(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", ".env\n")
+	repo.Commit("initial commit")
+	repo.CreateFile(".env", "SECRET=worktree")
+
+	// External secrets directory
+	copySource := filepath.Join(t.TempDir(), "secrets")
+	files := map[string]string{
+		".env":              "SECRET=external",
+		"certs/server.key":  "KEY",
+		"notes/skip.txt":    "skip",
+		".git/config":       "[core]",
+		"nested/.gitignore": "*",
+	}
+	for name, content := range files {
+		path := filepath.Join(copySource, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(copySource, "certs/server.key"), 0600); err != nil {
+		t.Fatalf("failed to chmod: %v", err)
+	}
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	opts := CopyOptions{CopyIgnored: true, CopySource: copySource, NoCopy: []string{"notes/"}}
+	if err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts); err != nil {
+		t.Fatalf("CopyFilesToWorktree failed: %v", err)
+	}
+
+	// The copy source overrides files copied from the source worktree
+	content, err := os.ReadFile(filepath.Join(dstDir, ".env"))
+	if err != nil {
+		t.Fatalf("failed to read .env: %v", err)
+	}
+	if string(content) != "SECRET=external" {
+		t.Errorf(".env content = %q, want %q", string(content), "SECRET=external")
+	}
+
+	info, err := os.Stat(filepath.Join(dstDir, "certs/server.key"))
+	if err != nil {
+		t.Fatalf("certs/server.key was not copied: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("certs/server.key mode = %v, want 0600", info.Mode().Perm())
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, "nested/.gitignore")); err != nil {
+		t.Errorf("nested/.gitignore was not copied: %v", err)
+	}
+
+	for _, skipped := range []string{"notes/skip.txt", ".git/config"} {
+		if _, err := os.Stat(filepath.Join(dstDir, skipped)); !os.IsNotExist(err) {
+			t.Errorf("%s should not be copied", skipped)
+		}
+	}
+
+	// A missing copy source is not an error
+	opts.CopySource = filepath.Join(t.TempDir(), "missing")
+	if err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts); err != nil {
+		t.Errorf("CopyFilesToWorktree failed for a missing copy source: %v", err)
+	}
+}
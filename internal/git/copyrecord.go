@@ -0,0 +1,107 @@
+package git
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// copyRecordFile is the file in the git directory of a worktree that records the content hash
+// of every file copied into the worktree, so that sync can tell files modified in the worktree
+// from files that changed in the source.
+const copyRecordFile = "wt-copied.json"
+
+// copyRecord maps the paths of files copied into a worktree (relative to its root)
+// to the SHA-256 hash of their content at copy time.
+type copyRecord struct {
+	path   string // Empty when the destination is not a worktree, so nothing is recorded
+	hashes map[string]string
+}
+
+// openCopyRecord reads the copy record of the worktree at root.
+// A missing record is empty, and a directory that is not the root of a worktree has no record.
+func openCopyRecord(ctx context.Context, root string) (*copyRecord, error) {
+	r := &copyRecord{hashes: make(map[string]string)}
+	cmd, err := gitCommand(ctx, "rev-parse", "--path-format=absolute", "--show-toplevel", "--git-dir")
+	if err != nil {
+		return nil, err
+	}
+	cmd.Dir = root
+	out, err := cmd.Output()
+	if err != nil {
+		return r, nil //nolint:nilerr // Not a worktree (e.g., a plain directory), nothing to record
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 2 || CanonicalPath(lines[0]) != CanonicalPath(root) {
+		return r, nil
+	}
+	r.path = filepath.Join(lines[1], copyRecordFile)
+
+	b, err := os.ReadFile(r.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return r, nil
+		}
+		return nil, fmt.Errorf("failed to read copy record: %w", err)
+	}
+	if err := json.Unmarshal(b, &r.hashes); err != nil {
+		return nil, fmt.Errorf("failed to parse copy record %s: %w", r.path, err)
+	}
+	return r, nil
+}
+
+// add records the content of the regular file at rel in root. Symlinks and missing files
+// (e.g., skipped copies) are not recorded.
+func (r *copyRecord) add(root, rel string) {
+	if r.path == "" {
+		return
+	}
+	path := filepath.Join(root, rel)
+	if info, err := os.Lstat(path); err != nil || !info.Mode().IsRegular() {
+		delete(r.hashes, rel)
+		return
+	}
+	hash, err := fileHash(path)
+	if err != nil {
+		delete(r.hashes, rel)
+		return
+	}
+	r.hashes[rel] = hex.EncodeToString(hash)
+}
+
+// matches reports whether rel is recorded with the content hash.
+func (r *copyRecord) matches(rel string, hash []byte) bool {
+	recorded, ok := r.hashes[rel]
+	return ok && recorded == hex.EncodeToString(hash)
+}
+
+// save writes the record to the git directory of the worktree.
+func (r *copyRecord) save() error {
+	if r.path == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(r.hashes, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(r.path, append(b, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write copy record: %w", err)
+	}
+	return nil
+}
+
+// recordCopies adds the files at rels in the worktree at root to its copy record.
+func recordCopies(ctx context.Context, root string, rels []string) error {
+	r, err := openCopyRecord(ctx, root)
+	if err != nil {
+		return err
+	}
+	for _, rel := range rels {
+		r.add(root, rel)
+	}
+	return r.save()
+}
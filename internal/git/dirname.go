@@ -0,0 +1,136 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultDirName is the default wt.dirname template: the worktree directory is named after the branch.
+const DefaultDirName = "{branch}"
+
+// maxDirNameSuffix is the largest numeric suffix tried when the worktree directory already exists.
+const maxDirNameSuffix = 100
+
+var (
+	// unsafePathChars are characters that are awkward in paths or invalid on some platforms.
+	unsafePathChars = regexp.MustCompile(`[\x00-\x1f\x7f\s\\:*?"<>|]+`)
+	// dirNameVariable matches variables left in an expanded wt.dirname template.
+	dirNameVariable = regexp.MustCompile(`\{[a-z_]+\}`)
+	// dirNameNow returns the time used for {date}.
+	dirNameNow = time.Now
+)
+
+// ExpandDirName expands a wt.dirname template for branch into a worktree directory name,
+// relative to the base directory.
+// Supported variables:
+//   - {branch}: branch name, "/" creates nested directories (e.g., feature/login)
+//   - {branch_flat}: branch name with "/" replaced by "-" (e.g., feature-login)
+//   - {short}: last segment of the branch name (e.g., login)
+//   - {date}: current date (YYYYMMDD)
+//   - {user}: current OS user name
+//
+// Characters that are awkward or invalid in paths (whitespace, control characters and \:*?"<>|)
+// are replaced by "-".
+func ExpandDirName(tmpl, branch string) (string, error) {
+	sanitized := sanitizePath(branch)
+	pairs := []string{
+		"{branch}", sanitized,
+		"{branch_flat}", strings.ReplaceAll(sanitized, "/", "-"),
+		"{short}", sanitized[strings.LastIndex(sanitized, "/")+1:],
+		"{date}", dirNameNow().Format("20060102"),
+	}
+	if strings.Contains(tmpl, "{user}") {
+		name, err := currentUserName()
+		if err != nil {
+			return "", err
+		}
+		pairs = append(pairs, "{user}", sanitizePath(name))
+	}
+	expanded := strings.NewReplacer(pairs...).Replace(tmpl)
+	if v := dirNameVariable.FindString(expanded); v != "" {
+		return "", fmt.Errorf("invalid %s %q: unknown variable %s", configKeyDirName, tmpl, v)
+	}
+
+	name := filepath.Clean(filepath.FromSlash(expanded))
+	if name == "." || filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid %s %q: %q is not a directory inside the base directory", configKeyDirName, tmpl, expanded)
+	}
+	return name, nil
+}
+
+// sanitizePath replaces characters that are awkward or invalid in paths by "-".
+func sanitizePath(s string) string {
+	return unsafePathChars.ReplaceAllString(s, "-")
+}
+
+// currentUserName returns the name of the current OS user.
+func currentUserName() (string, error) {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		// Strip the domain on Windows (DOMAIN\user)
+		return u.Username[strings.LastIndex(u.Username, `\`)+1:], nil
+	}
+	for _, env := range []string{"USER", "USERNAME"} {
+		if name := os.Getenv(env); name != "" {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("failed to get the current user name for %s", configKeyDirName)
+}
+
+// ResolveWorktreePath returns the path at which to create a new worktree.
+// If path is already taken by an existing file, directory or worktree (e.g., two branches with the
+// same {short} name, or a leftover directory), a numeric suffix is appended ("-2", "-3", ...).
+// It fails if path is inside another worktree (e.g., creating "review/x" with wt.dirname "{branch}"
+// while another branch has a worktree at "review"), since a suffix cannot avoid that collision.
+func ResolveWorktreePath(ctx context.Context, path string) (string, error) {
+	worktrees, err := ListWorktrees(ctx)
+	if err != nil {
+		return "", err
+	}
+	// The main worktree (first) usually contains the base directory (e.g., .wt)
+	if len(worktrees) > 0 {
+		worktrees = worktrees[1:]
+	}
+	candidate := path
+	for i := 2; ; i++ {
+		taken := false
+		for _, wt := range worktrees {
+			if wt.Path == candidate {
+				taken = true
+				continue
+			}
+			if isSubPath(wt.Path, candidate) {
+				return "", fmt.Errorf("worktree path %s is inside the worktree %s; use a wt.dirname such as \"{branch_flat}\" to avoid nested worktrees", candidate, wt.Path)
+			}
+		}
+		if !taken {
+			_, err := os.Lstat(candidate)
+			if os.IsNotExist(err) {
+				return candidate, nil
+			}
+			if err != nil {
+				return "", err
+			}
+		}
+		if i > maxDirNameSuffix {
+			return "", fmt.Errorf("worktree path %s already exists", path)
+		}
+		candidate = path + "-" + strconv.Itoa(i)
+	}
+}
+
+// isSubPath reports whether path is inside dir (and is not dir itself).
+func isSubPath(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
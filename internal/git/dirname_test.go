@@ -0,0 +1,100 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestExpandDirName(t *testing.T) {
+	now := dirNameNow
+	dirNameNow = func() time.Time { return time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { dirNameNow = now })
+
+	tests := []struct {
+		tmpl    string
+		branch  string
+		want    string
+		wantErr bool
+	}{
+		{"{branch}", "feature/login", filepath.Join("feature", "login"), false},
+		{"{branch_flat}", "feature/login", "feature-login", false},
+		{"{short}", "feature/login", "login", false},
+		{"{short}", "main", "main", false},
+		{"{date}-{short}", "feature/login", "20261018-login", false},
+		{"review/{branch_flat}", "fix/a/b", filepath.Join("review", "fix-a-b"), false},
+		{"{branch_flat}", `odd"name|x`, "odd-name-x", false},
+		{"{unknown}", "main", "", true},
+		{"../{branch}", "main", "", true},
+		{"/abs/{branch}", "main", "", true},
+		{"", "main", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ExpandDirName(tt.tmpl, tt.branch)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ExpandDirName(%q, %q) error = %v, wantErr %v", tt.tmpl, tt.branch, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ExpandDirName(%q, %q) = %q, want %q", tt.tmpl, tt.branch, got, tt.want)
+		}
+	}
+
+	t.Run("user", func(t *testing.T) {
+		name, err := currentUserName()
+		if err != nil {
+			t.Skipf("no user name: %v", err)
+		}
+		got, err := ExpandDirName("{user}-{short}", "feature/login")
+		if err != nil {
+			t.Fatalf("ExpandDirName() failed: %v", err)
+		}
+		if want := sanitizePath(name) + "-login"; got != want {
+			t.Errorf("ExpandDirName() = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestResolveWorktreePath(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	baseDir := filepath.Join(repo.ParentDir(), "wt")
+	repo.Git("worktree", "add", "-b", "feature", filepath.Join(baseDir, "feature"))
+	if err := os.MkdirAll(filepath.Join(baseDir, "leftover"), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(baseDir, "leftover-2"), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"free", filepath.Join(baseDir, "other"), filepath.Join(baseDir, "other"), false},
+		{"existing_worktree", filepath.Join(baseDir, "feature"), filepath.Join(baseDir, "feature-2"), false},
+		{"existing_directories", filepath.Join(baseDir, "leftover"), filepath.Join(baseDir, "leftover-3"), false},
+		{"nested_in_worktree", filepath.Join(baseDir, "feature", "x"), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveWorktreePath(t.Context(), tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveWorktreePath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveWorktreePath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,10 +2,15 @@ package git
 
 import (
 	"context"
+	"sync/atomic"
 
 	"github.com/k1LoW/exec"
 )
 
+// gitCommandCount is the number of git commands created by gitCommand.
+// It is used by benchmarks to count process spawns.
+var gitCommandCount atomic.Int64
+
 // gitCommand creates an exec.Cmd for git with the given context and arguments.
 // It uses exec.LookPath to look up the git binary path.
 func gitCommand(ctx context.Context, args ...string) (*exec.Cmd, error) {
@@ -13,5 +18,6 @@ func gitCommand(ctx context.Context, args ...string) (*exec.Cmd, error) {
 	if err != nil {
 		return nil, err
 	}
+	gitCommandCount.Add(1)
 	return exec.CommandContext(ctx, gitPath, args...), nil
 }
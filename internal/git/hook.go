@@ -4,17 +4,22 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/k1LoW/exec"
 )
 
 // RunHooks executes the configured hooks in the given directory.
+// env is added to the environment of the hooks (e.g., allocated ports).
 // Hook stdout/stderr are written to the provided writer.
 // If a hook fails, it stops immediately and returns the error.
-func RunHooks(ctx context.Context, hooks []string, dir string, w io.Writer) error {
+func RunHooks(ctx context.Context, hooks []string, dir string, env []string, w io.Writer) error {
 	for _, hook := range hooks {
 		cmd := exec.CommandContext(ctx, "sh", "-c", hook)
 		cmd.Dir = dir
+		if len(env) > 0 {
+			cmd.Env = append(os.Environ(), env...)
+		}
 		cmd.Stdout = w
 		cmd.Stderr = w
 		if err := cmd.Run(); err != nil {
@@ -0,0 +1,137 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// worktreeIncludeFile is the repository manifest of files to copy to new worktrees (gitignore syntax).
+	worktreeIncludeFile = ".worktreeinclude"
+	// copyAttribute is the git attribute that marks files to copy to new worktrees.
+	copyAttribute = "wt-copy"
+)
+
+// readWorktreeInclude reads the patterns in the .worktreeinclude file at root.
+// Blank lines and comments are skipped. A missing file has no patterns.
+func readWorktreeInclude(root string) ([]string, error) {
+	f, err := os.Open(filepath.Join(root, worktreeIncludeFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", worktreeIncludeFile, err)
+	}
+	defer f.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", worktreeIncludeFile, err)
+	}
+	return patterns, nil
+}
+
+// usesCopyAttribute reports whether an attribute file of the worktree at root mentions the
+// wt-copy attribute. It avoids running git check-attr for repositories that do not use it.
+func usesCopyAttribute(ctx context.Context, root string) (bool, error) {
+	files, err := listAttributeFiles(ctx, root)
+	if err != nil {
+		return false, err
+	}
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if bytes.Contains(b, []byte(copyAttribute)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// listAttributeFiles returns the paths of the attribute files git reads for the worktree at root:
+// .gitattributes files in the worktree (including nested ones), $GIT_DIR/info/attributes and the
+// global attributes file (core.attributesFile). The files do not necessarily exist.
+func listAttributeFiles(ctx context.Context, root string) ([]string, error) {
+	cmd, err := gitCommand(ctx, "ls-files", "-z", "--cached", "--others", "--exclude-standard", "--", ":(glob)**/.gitattributes")
+	if err != nil {
+		return nil, err
+	}
+	cmd.Dir = root
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list .gitattributes files: %w", err)
+	}
+	var files []string
+	for _, file := range strings.Split(string(out), "\x00") {
+		if file != "" {
+			files = append(files, filepath.Join(root, file))
+		}
+	}
+
+	cmd, err = gitCommand(ctx, "rev-parse", "--path-format=absolute", "--git-path", "info/attributes")
+	if err != nil {
+		return nil, err
+	}
+	cmd.Dir = root
+	out, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get info/attributes path: %w", err)
+	}
+	files = append(files, strings.TrimSpace(string(out)))
+
+	cmd, err = gitCommand(ctx, "config", "--path", "--get", "core.attributesFile")
+	if err != nil {
+		return nil, err
+	}
+	cmd.Dir = root
+	if out, err := cmd.Output(); err == nil {
+		files = append(files, strings.TrimSpace(string(out)))
+	} else if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		files = append(files, filepath.Join(xdg, "git", "attributes"))
+	} else if home, err := os.UserHomeDir(); err == nil {
+		files = append(files, filepath.Join(home, ".config", "git", "attributes"))
+	}
+	return files, nil
+}
+
+// filterByCopyAttribute returns the files that have the wt-copy attribute set.
+func filterByCopyAttribute(ctx context.Context, root string, files []string) ([]string, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	cmd, err := gitCommand(ctx, "check-attr", "-z", "--stdin", copyAttribute)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Dir = root
+	cmd.Stdin = strings.NewReader(strings.Join(files, "\x00") + "\x00")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s attribute: %w", copyAttribute, err)
+	}
+
+	// Output is a sequence of <path> NUL <attribute> NUL <info> NUL
+	fields := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	var result []string
+	for i := 0; i+2 < len(fields); i += 3 {
+		if info := fields[i+2]; info == "set" || info == "true" {
+			result = append(result, fields[i])
+		}
+	}
+	return result, nil
+}
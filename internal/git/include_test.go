@@ -0,0 +1,146 @@
+package git
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestReadWorktreeInclude(t *testing.T) {
+	root := t.TempDir()
+
+	patterns, err := readWorktreeInclude(root)
+	if err != nil {
+		t.Fatalf("readWorktreeInclude failed: %v", err)
+	}
+	if patterns != nil {
+		t.Errorf("readWorktreeInclude() = %v, want nil for a missing file", patterns)
+	}
+
+	content := "# Local settings\n.env\r\n\n*.local  \n!example.local\n"
+	if err := os.WriteFile(filepath.Join(root, worktreeIncludeFile), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	patterns, err = readWorktreeInclude(root)
+	if err != nil {
+		t.Fatalf("readWorktreeInclude failed: %v", err)
+	}
+	want := []string{".env", "*.local", "!example.local"}
+	if !reflect.DeepEqual(patterns, want) {
+		t.Errorf("readWorktreeInclude() = %v, want %v", patterns, want)
+	}
+}
+
+func TestCopyFilesToWorktree_WorktreeInclude(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", ".env\n*.local\ncerts/\nbuild/\n")
+	repo.CreateFile(worktreeIncludeFile, ".env\n*.local\n!example.local\n")
+	repo.CreateFile(".gitattributes", "certs/** wt-copy\n")
+	repo.Commit("initial commit")
+
+	repo.CreateFile(".env", "SECRET=value")
+	repo.CreateFile("settings.local", "local")
+	repo.CreateFile("example.local", "example")
+	repo.CreateFile("certs/server.key", "KEY")
+	repo.CreateFile("build/output.bin", "binary")
+	repo.CreateFile("scratch.txt", "untracked")
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	var log bytes.Buffer
+	opts := CopyOptions{Copy: []string{"scratch.txt"}, Log: &log}
+	if err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts); err != nil {
+		t.Fatalf("CopyFilesToWorktree failed: %v", err)
+	}
+
+	for _, copied := range []string{".env", "settings.local", "certs/server.key", "scratch.txt"} {
+		if _, err := os.Stat(filepath.Join(dstDir, copied)); err != nil {
+			t.Errorf("%s was not copied: %v", copied, err)
+		}
+	}
+	for _, skipped := range []string{"example.local", "build/output.bin"} {
+		if _, err := os.Stat(filepath.Join(dstDir, skipped)); !os.IsNotExist(err) {
+			t.Errorf("%s should not be copied", skipped)
+		}
+	}
+
+	// Verbose output shows which source selected each file
+	for _, want := range []string{
+		"copy .env (",
+		"from .worktreeinclude)",
+		"from wt-copy attribute)",
+		"from wt.copy)",
+	} {
+		if !strings.Contains(log.String(), want) {
+			t.Errorf("log does not contain %q:\n%s", want, log.String())
+		}
+	}
+
+	// NoCopy still wins over the manifest
+	dstDir2 := filepath.Join(repo.ParentDir(), "dst2")
+	if err := os.MkdirAll(dstDir2, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+	opts = CopyOptions{NoCopy: []string{".env"}}
+	if err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir2, opts); err != nil {
+		t.Fatalf("CopyFilesToWorktree failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dstDir2, ".env")); !os.IsNotExist(err) {
+		t.Error(".env should not be copied when it matches wt.nocopy")
+	}
+	if _, err := os.Stat(filepath.Join(dstDir2, "settings.local")); err != nil {
+		t.Errorf("settings.local was not copied: %v", err)
+	}
+}
+
+func TestCopyFilesToWorktree_CopyAttributeOutsideRoot(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", "*.key\n*.pem\n*.log\n")
+	repo.CreateFile("config/.gitattributes", "*.key wt-copy\n")
+	repo.Commit("initial commit")
+	if err := os.WriteFile(filepath.Join(repo.Root, ".git", "info", "attributes"), []byte("*.pem wt-copy\n"), 0644); err != nil {
+		t.Fatalf("failed to write info/attributes: %v", err)
+	}
+
+	repo.CreateFile("config/server.key", "KEY")
+	repo.CreateFile("server.key", "KEY")
+	repo.CreateFile("cert.pem", "CERT")
+	repo.CreateFile("debug.log", "log")
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	if err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, CopyOptions{}); err != nil {
+		t.Fatalf("CopyFilesToWorktree failed: %v", err)
+	}
+
+	// A nested .gitattributes applies to its directory, and info/attributes to the whole repository
+	for _, copied := range []string{"config/server.key", "cert.pem"} {
+		if _, err := os.Stat(filepath.Join(dstDir, copied)); err != nil {
+			t.Errorf("%s was not copied: %v", copied, err)
+		}
+	}
+	for _, skipped := range []string{"server.key", "debug.log"} {
+		if _, err := os.Stat(filepath.Join(dstDir, skipped)); !os.IsNotExist(err) {
+			t.Errorf("%s should not be copied", skipped)
+		}
+	}
+}
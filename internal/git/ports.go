@@ -0,0 +1,283 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPortBase is the first port handed out by the port registry.
+	DefaultPortBase = 20000
+	// maxPort is the highest valid TCP port.
+	maxPort = 65535
+
+	portRegistryFile = "wt-ports.json"
+	portLockFile     = "wt-ports.lock"
+	portLockTimeout  = 10 * time.Second
+)
+
+// PortBlock is a contiguous block of ports allocated to a worktree.
+type PortBlock struct {
+	Base int `json:"base"`
+	Size int `json:"size"`
+}
+
+// Ports returns the ports in the block.
+func (b PortBlock) Ports() []int {
+	ports := make([]int, b.Size)
+	for i := range ports {
+		ports[i] = b.Base + i
+	}
+	return ports
+}
+
+// Env returns the environment variables exposing the ports to hooks:
+// GIT_WT_PORT (first port), GIT_WT_PORT_<i> (each port) and GIT_WT_PORTS (comma-separated).
+func (b PortBlock) Env() []string {
+	if b.Size == 0 {
+		return nil
+	}
+	ports := b.Ports()
+	env := []string{fmt.Sprintf("GIT_WT_PORT=%d", ports[0])}
+	strs := make([]string, len(ports))
+	for i, p := range ports {
+		env = append(env, fmt.Sprintf("GIT_WT_PORT_%d=%d", i, p))
+		strs[i] = strconv.Itoa(p)
+	}
+	return append(env, "GIT_WT_PORTS="+strings.Join(strs, ","))
+}
+
+func (b PortBlock) overlaps(o PortBlock) bool {
+	return b.Base < o.Base+o.Size && o.Base < b.Base+b.Size
+}
+
+// PortRegistry allocates port blocks to worktrees.
+// Allocations are stored in the git common directory, so they are shared by all worktrees,
+// and every change is made while holding a lock file.
+type PortRegistry struct {
+	path string
+	lock string
+}
+
+// OpenPortRegistry returns the port registry of the current repository.
+func OpenPortRegistry(ctx context.Context) (*PortRegistry, error) {
+	cmd, err := gitCommand(ctx, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git common directory: %w", err)
+	}
+	commonDir := strings.TrimSpace(string(out))
+	return &PortRegistry{
+		path: filepath.Join(commonDir, portRegistryFile),
+		lock: filepath.Join(commonDir, portLockFile),
+	}, nil
+}
+
+// Allocate returns the port block of the worktree at path, allocating a new block of size ports
+// starting at or after base if it has none. An existing block of the same size is kept,
+// so the ports of a worktree are stable.
+func (r *PortRegistry) Allocate(path string, size, base int) (PortBlock, error) {
+	if size <= 0 {
+		return PortBlock{}, fmt.Errorf("invalid port block size: %d", size)
+	}
+	if base <= 0 || base > maxPort {
+		return PortBlock{}, fmt.Errorf("invalid port base: %d", base)
+	}
+	key := CanonicalPath(path)
+
+	var block PortBlock
+	err := r.update(func(blocks map[string]PortBlock) error {
+		if b, ok := blocks[key]; ok && b.Size == size {
+			block = b
+			return nil
+		}
+		delete(blocks, key)
+
+		candidate := PortBlock{Base: base, Size: size}
+		for {
+			if candidate.Base+candidate.Size-1 > maxPort {
+				return fmt.Errorf("no free block of %d ports from %d", size, base)
+			}
+			conflict := false
+			for _, b := range blocks {
+				if candidate.overlaps(b) {
+					conflict = true
+					break
+				}
+			}
+			if !conflict {
+				break
+			}
+			candidate.Base += size
+		}
+		blocks[key] = candidate
+		block = candidate
+		return nil
+	})
+	return block, err
+}
+
+// Release frees the port block of the worktree at path.
+// Nothing is written when no ports have ever been allocated (e.g., wt.portblock is not used).
+func (r *PortRegistry) Release(path string) error {
+	if !r.exists() {
+		return nil
+	}
+	key := CanonicalPath(path)
+	return r.update(func(blocks map[string]PortBlock) error {
+		delete(blocks, key)
+		return nil
+	})
+}
+
+// Prune frees the port blocks of worktrees not in paths (e.g., removed with "git worktree remove")
+// and returns the paths of the freed blocks.
+func (r *PortRegistry) Prune(paths []string) ([]string, error) {
+	if !r.exists() {
+		return nil, nil
+	}
+	keep := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		keep[CanonicalPath(p)] = struct{}{}
+	}
+	var pruned []string
+	err := r.update(func(blocks map[string]PortBlock) error {
+		for p := range blocks {
+			if _, ok := keep[p]; !ok {
+				delete(blocks, p)
+				pruned = append(pruned, p)
+			}
+		}
+		return nil
+	})
+	sort.Strings(pruned)
+	return pruned, err
+}
+
+// Lookup returns the port block of the worktree at path.
+func (r *PortRegistry) Lookup(path string) (PortBlock, bool, error) {
+	blocks, err := r.load()
+	if err != nil {
+		return PortBlock{}, false, err
+	}
+	b, ok := blocks[CanonicalPath(path)]
+	return b, ok, nil
+}
+
+// List returns the port blocks of all worktrees, keyed by worktree path.
+func (r *PortRegistry) List() (map[string]PortBlock, error) {
+	return r.load()
+}
+
+// exists reports whether the registry file exists, i.e., ports have been allocated.
+func (r *PortRegistry) exists() bool {
+	_, err := os.Stat(r.path)
+	return err == nil
+}
+
+// load reads the registry file. A missing file is an empty registry.
+func (r *PortRegistry) load() (map[string]PortBlock, error) {
+	blocks := make(map[string]PortBlock)
+	b, err := os.ReadFile(r.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return blocks, nil
+		}
+		return nil, fmt.Errorf("failed to read port registry: %w", err)
+	}
+	if err := json.Unmarshal(b, &blocks); err != nil {
+		return nil, fmt.Errorf("failed to parse port registry %s: %w", r.path, err)
+	}
+	return blocks, nil
+}
+
+// update applies fn to the registry while holding the lock and writes the result atomically.
+func (r *PortRegistry) update(fn func(blocks map[string]PortBlock) error) error {
+	unlock, err := r.acquire()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	blocks, err := r.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(blocks); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(blocks, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write port registry: %w", err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		return fmt.Errorf("failed to write port registry: %w", err)
+	}
+	return nil
+}
+
+// acquire takes the registry lock with a file lock on the lock file (see tryLockFile).
+// The lock file is kept; the lock is released when the returned function closes it.
+func (r *PortRegistry) acquire() (func(), error) {
+	f, err := os.OpenFile(r.lock, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock port registry: %w", err)
+	}
+	deadline := time.Now().Add(portLockTimeout)
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to lock port registry: %w", err)
+		}
+		if locked {
+			return func() { _ = f.Close() }, nil
+		}
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			return nil, fmt.Errorf("timed out waiting for port registry lock %s", r.lock)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// SortedPaths returns the worktree paths of blocks ordered by base port.
+func SortedPaths(blocks map[string]PortBlock) []string {
+	paths := make([]string, 0, len(blocks))
+	for p := range blocks {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return blocks[paths[i]].Base < blocks[paths[j]].Base
+	})
+	return paths
+}
+
+// CanonicalPath resolves symlinks in the longest existing prefix of path,
+// so that the same worktree gets the same key before and after it is created.
+func CanonicalPath(path string) string {
+	path = filepath.Clean(path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path
+	}
+	return filepath.Join(CanonicalPath(parent), filepath.Base(path))
+}
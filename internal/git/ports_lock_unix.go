@@ -0,0 +1,23 @@
+//go:build unix
+
+// Unix implementation using flock(2). The lock is released by the kernel when the
+// process exits, so a crashed process does not leave the registry locked.
+
+package git
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// tryLockFile takes an exclusive lock on f without blocking.
+// It returns false if another process (or file descriptor) holds the lock.
+func tryLockFile(f *os.File) (bool, error) {
+	err := unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+	if errors.Is(err, unix.EWOULDBLOCK) {
+		return false, nil
+	}
+	return err == nil, err
+}
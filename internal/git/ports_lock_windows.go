@@ -0,0 +1,24 @@
+//go:build windows
+
+// Windows implementation using LockFileEx. The lock is released by the system when the
+// process exits, so a crashed process does not leave the registry locked.
+
+package git
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// tryLockFile takes an exclusive lock on f without blocking.
+// It returns false if another process (or file handle) holds the lock.
+func tryLockFile(f *os.File) (bool, error) {
+	flags := uint32(windows.LOCKFILE_EXCLUSIVE_LOCK | windows.LOCKFILE_FAIL_IMMEDIATELY)
+	err := windows.LockFileEx(windows.Handle(f.Fd()), flags, 0, 1, 0, &windows.Overlapped{})
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+	return err == nil, err
+}
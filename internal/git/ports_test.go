@@ -0,0 +1,186 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func newTestPortRegistry(t *testing.T) (*testutil.TestRepo, *PortRegistry) {
+	t.Helper()
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	restore := repo.Chdir()
+	t.Cleanup(restore)
+
+	registry, err := OpenPortRegistry(t.Context())
+	if err != nil {
+		t.Fatalf("OpenPortRegistry failed: %v", err)
+	}
+	return repo, registry
+}
+
+func TestPortRegistry_Allocate(t *testing.T) {
+	repo, registry := newTestPortRegistry(t)
+	pathA := filepath.Join(repo.ParentDir(), "a")
+	pathB := filepath.Join(repo.ParentDir(), "b")
+
+	// Releasing without any allocation does not create the registry
+	if err := registry.Release(pathA); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if registry.exists() {
+		t.Error("Release should not create the registry")
+	}
+
+	a, err := registry.Allocate(pathA, 3, 20000)
+	if err != nil {
+		t.Fatalf("Allocate failed: %v", err)
+	}
+	if a != (PortBlock{Base: 20000, Size: 3}) {
+		t.Errorf("Allocate(a) = %+v, want base 20000", a)
+	}
+	b, err := registry.Allocate(pathB, 3, 20000)
+	if err != nil {
+		t.Fatalf("Allocate failed: %v", err)
+	}
+	if b != (PortBlock{Base: 20003, Size: 3}) {
+		t.Errorf("Allocate(b) = %+v, want base 20003", b)
+	}
+
+	// Allocation is stable
+	again, err := registry.Allocate(pathA, 3, 20000)
+	if err != nil {
+		t.Fatalf("Allocate failed: %v", err)
+	}
+	if again != a {
+		t.Errorf("Allocate(a) again = %+v, want %+v", again, a)
+	}
+
+	// Released blocks are reused
+	if err := registry.Release(pathA); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, ok, err := registry.Lookup(pathA); err != nil || ok {
+		t.Errorf("Lookup(a) after Release = %v, %v, want not found", ok, err)
+	}
+	c, err := registry.Allocate(filepath.Join(repo.ParentDir(), "c"), 3, 20000)
+	if err != nil {
+		t.Fatalf("Allocate failed: %v", err)
+	}
+	if c.Base != 20000 {
+		t.Errorf("Allocate(c).Base = %d, want 20000", c.Base)
+	}
+
+	// Blocks of worktrees not in the list are pruned
+	pruned, err := registry.Prune([]string{pathB})
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if len(pruned) != 1 || filepath.Base(pruned[0]) != "c" {
+		t.Errorf("Prune() = %v, want [c]", pruned)
+	}
+
+	if _, err := registry.Allocate(pathA, 3, 65535); err == nil {
+		t.Error("Allocate should fail when the block exceeds the port range")
+	}
+}
+
+func TestPortRegistry_AllocateConcurrently(t *testing.T) {
+	repo, registry := newTestPortRegistry(t)
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := registry.Allocate(filepath.Join(repo.ParentDir(), fmt.Sprintf("wt%d", i)), 2, 30000); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Allocate failed: %v", err)
+	}
+
+	blocks, err := registry.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(blocks) != n {
+		t.Fatalf("List() returned %d blocks, want %d", len(blocks), n)
+	}
+	seen := make(map[int]string)
+	for p, b := range blocks {
+		for _, port := range b.Ports() {
+			if other, ok := seen[port]; ok {
+				t.Errorf("port %d allocated to both %s and %s", port, other, p)
+			}
+			seen[port] = p
+		}
+	}
+}
+
+func TestPortRegistry_Lock(t *testing.T) {
+	repo, registry := newTestPortRegistry(t)
+
+	// A lock file left by a crashed process does not block allocations
+	if err := os.WriteFile(registry.lock, nil, 0600); err != nil {
+		t.Fatalf("failed to write lock file: %v", err)
+	}
+	start := time.Now()
+	if _, err := registry.Allocate(filepath.Join(repo.ParentDir(), "a"), 1, 30000); err != nil {
+		t.Fatalf("Allocate failed: %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Allocate took %v with a leftover lock file, want no wait", d)
+	}
+
+	// A held lock makes other holders wait until it is released
+	unlock, err := registry.acquire()
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := registry.Allocate(filepath.Join(repo.ParentDir(), "b"), 1, 30000)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("Allocate should wait for the lock, got error %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	if err := <-done; err != nil {
+		t.Fatalf("Allocate failed after the lock was released: %v", err)
+	}
+}
+
+func TestPortBlock_Env(t *testing.T) {
+	got := PortBlock{Base: 20000, Size: 2}.Env()
+	want := []string{
+		"GIT_WT_PORT=20000",
+		"GIT_WT_PORT_0=20000",
+		"GIT_WT_PORT_1=20001",
+		"GIT_WT_PORTS=20000,20001",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Env() = %v, want %v", got, want)
+	}
+	if env := (PortBlock{}).Env(); env != nil {
+		t.Errorf("Env() of an empty block = %v, want nil", env)
+	}
+}
@@ -0,0 +1,80 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Forges (wt.forge) define the refs that pull requests are fetched from.
+const (
+	ForgeGitHub = "github" // refs/pull/<N>/head
+	ForgeGitLab = "gitlab" // refs/merge-requests/<N>/head
+)
+
+// prBranchPrefix is the prefix of the local branches that pull requests are fetched into.
+const prBranchPrefix = "pr/"
+
+// PRBranch returns the local branch that pull request number is fetched into (e.g., pr/123).
+func PRBranch(number int) string {
+	return prBranchPrefix + strconv.Itoa(number)
+}
+
+// ParsePRBranch returns the number of the pull request of a branch named like PRBranch (e.g., 123 for pr/123).
+func ParsePRBranch(branch string) (int, bool) {
+	s, ok := strings.CutPrefix(branch, prBranchPrefix)
+	if !ok {
+		return 0, false
+	}
+	number, err := strconv.Atoi(s)
+	if err != nil || number <= 0 || strconv.Itoa(number) != s {
+		return 0, false
+	}
+	return number, true
+}
+
+// PRRef returns the ref of pull request number on the remote, and how the forge calls it
+// (e.g., "refs/pull/123/head" and "pull request #123" for GitHub).
+func PRRef(forge string, number int) (string, string, error) {
+	switch forge {
+	case ForgeGitHub:
+		return fmt.Sprintf("refs/pull/%d/head", number), fmt.Sprintf("pull request #%d", number), nil
+	case ForgeGitLab:
+		return fmt.Sprintf("refs/merge-requests/%d/head", number), fmt.Sprintf("merge request !%d", number), nil
+	}
+	return "", "", fmt.Errorf("invalid %s: unknown forge %q", configKeyForge, forge)
+}
+
+// FetchPR fetches pull request number from remote into its local branch (see PRBranch),
+// creating the branch or fast-forwarding it.
+// Git messages are written to stderr.
+func FetchPR(ctx context.Context, remote, forge string, number int) error {
+	ref, name, err := PRRef(forge, number)
+	if err != nil {
+		return err
+	}
+	// wt.remote may come from the repository config file, so only fetch from a configured remote
+	remotes, err := ListRemotes(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(remotes, remote) {
+		return fmt.Errorf("unknown remote %q (see %s)", remote, configKeyRemote)
+	}
+	branch := PRBranch(number)
+	fmt.Fprintf(os.Stderr, "Fetching %s from %s into %s\n", name, remote, branch)
+	cmd, err := gitCommand(ctx, "fetch", "--end-of-options", remote, ref+":refs/heads/"+branch)
+	if err != nil {
+		return err
+	}
+	// Output git messages to stderr so stdout only contains the path for shell integration
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to fetch %s (%s) from %s: %w", name, ref, remote, err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestParsePRBranch(t *testing.T) {
+	tests := []struct {
+		branch string
+		want   int
+		wantOk bool
+	}{
+		{"pr/123", 123, true},
+		{"pr/1", 1, true},
+		{"pr/0", 0, false},
+		{"pr/0123", 0, false},
+		{"pr/-1", 0, false},
+		{"pr/abc", 0, false},
+		{"pr/123/fix", 0, false},
+		{"feature/123", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParsePRBranch(tt.branch)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("ParsePRBranch(%q) = %d, %v, want %d, %v", tt.branch, got, ok, tt.want, tt.wantOk)
+		}
+		if ok && PRBranch(got) != tt.branch {
+			t.Errorf("PRBranch(%d) = %q, want %q", got, PRBranch(got), tt.branch)
+		}
+	}
+}
+
+func TestPRRef(t *testing.T) {
+	tests := []struct {
+		forge   string
+		want    string
+		wantErr bool
+	}{
+		{ForgeGitHub, "refs/pull/42/head", false},
+		{ForgeGitLab, "refs/merge-requests/42/head", false},
+		{"bitbucket", "", true},
+	}
+	for _, tt := range tests {
+		got, _, err := PRRef(tt.forge, 42)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("PRRef(%q) error = %v, wantErr %v", tt.forge, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("PRRef(%q) = %q, want %q", tt.forge, got, tt.want)
+		}
+	}
+}
+
+func TestFetchPR(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+	bare := filepath.Join(t.TempDir(), "remote.git")
+	repo.Git("init", "--bare", "--initial-branch=main", bare)
+	repo.Git("remote", "add", "origin", bare)
+	repo.Git("push", "origin", "main")
+
+	// Pull and merge requests are only available as refs on the remote
+	repo.Git("checkout", "-b", "contribution")
+	repo.CreateFile("contribution.txt", "contribution")
+	repo.Commit("contribution")
+	want := repo.Git("rev-parse", "HEAD")
+	repo.Git("push", "origin", "HEAD:refs/pull/7/head", "HEAD:refs/merge-requests/8/head")
+	repo.Git("checkout", "main")
+	repo.Git("branch", "-D", "contribution")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	tests := []struct {
+		forge  string
+		number int
+	}{
+		{ForgeGitHub, 7},
+		{ForgeGitLab, 8},
+	}
+	for _, tt := range tests {
+		if err := FetchPR(t.Context(), DefaultRemote, tt.forge, tt.number); err != nil {
+			t.Fatalf("FetchPR(%q, %d) failed: %v", tt.forge, tt.number, err)
+		}
+		if got := repo.Git("rev-parse", PRBranch(tt.number)); got != want {
+			t.Errorf("%s = %s, want %s", PRBranch(tt.number), got, want)
+		}
+	}
+
+	if err := FetchPR(t.Context(), DefaultRemote, ForgeGitHub, 9); err == nil {
+		t.Error("FetchPR() should fail for a pull request that does not exist")
+	}
+
+	// wt.remote may come from the repository config file, so options and unknown remotes are rejected
+	marker := filepath.Join(t.TempDir(), "pwned")
+	for _, remote := range []string{"--upload-pack=touch " + marker + ";", "upstream"} {
+		if err := FetchPR(t.Context(), remote, ForgeGitHub, 7); err == nil {
+			t.Errorf("FetchPR(%q) should fail for an unknown remote", remote)
+		}
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Error("FetchPR() should not run the upload pack of a hostile remote")
+	}
+}
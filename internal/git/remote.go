@@ -0,0 +1,123 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultRemote is the default wt.remote.
+const DefaultRemote = "origin"
+
+// localRemoteHost is the {host} of repositories whose remote is a local path (or that have no remote).
+const localRemoteHost = "localhost"
+
+// scpLikeURL matches scp-like ssh URLs (e.g., git@github.com:owner/repo.git).
+var scpLikeURL = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// RemoteURL is a remote URL split into the parts used by basedir template variables.
+type RemoteURL struct {
+	Host  string // e.g., github.com ("localhost" for local paths)
+	Owner string // e.g., k1LoW (nested groups are kept, e.g., group/subgroup)
+	Repo  string // e.g., git-wt (without .git)
+}
+
+// ParseRemoteURL parses a remote URL such as https://github.com/owner/repo.git,
+// ssh://git@github.com/owner/repo.git, git@github.com:owner/repo.git, file:///path/to/owner/repo.git
+// or a local path.
+func ParseRemoteURL(raw string) (RemoteURL, error) {
+	raw = strings.TrimSpace(raw)
+	var host, p string
+	switch {
+	case strings.Contains(raw, "://"):
+		u, err := url.Parse(raw)
+		if err != nil {
+			return RemoteURL{}, fmt.Errorf("failed to parse remote URL %q: %w", raw, err)
+		}
+		host, p = u.Hostname(), u.Path
+		if u.Scheme == "file" {
+			host = localRemoteHost
+		}
+	case filepath.IsAbs(raw):
+		host, p = localRemoteHost, filepath.ToSlash(raw)
+	default:
+		if m := scpLikeURL.FindStringSubmatch(raw); m != nil {
+			host, p = m[1], m[2]
+		} else {
+			host, p = localRemoteHost, filepath.ToSlash(raw)
+		}
+	}
+
+	p = strings.TrimSuffix(strings.Trim(p, "/"), ".git")
+	i := strings.LastIndex(p, "/")
+	if host == "" || p == "" || i == len(p)-1 {
+		return RemoteURL{}, fmt.Errorf("failed to parse remote URL %q", raw)
+	}
+	r := RemoteURL{Host: host, Repo: p[i+1:]}
+	if i > 0 {
+		r.Owner = p[:i]
+	}
+	if r.Host == localRemoteHost {
+		// Only the parent directory of a local repository is its owner
+		r.Owner = r.Owner[strings.LastIndex(r.Owner, "/")+1:]
+		if r.Owner == "." || r.Owner == ".." {
+			r.Owner = ""
+		}
+	}
+	return r, nil
+}
+
+// ListRemotes returns the names of the remotes.
+func ListRemotes(ctx context.Context) ([]string, error) {
+	remotes, err := gitConfigValues(ctx, "remote")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
+	}
+	return remotes, nil
+}
+
+// configuredRemote returns the remote preferred for remote branches and the default branch (wt.remote).
+func configuredRemote(ctx context.Context) (string, error) {
+	cfg, err := LoadConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	return cfg.Remote, nil
+}
+
+// repoRemoteURL returns the parts of the URL of the remote set in wt.remote (origin by default), or of
+// the first remote if it does not exist. Repositories without remote are treated like a local remote at
+// the main repository root.
+func repoRemoteURL(ctx context.Context) (RemoteURL, error) {
+	remote, err := configuredRemote(ctx)
+	if err != nil {
+		return RemoteURL{}, err
+	}
+	raw, err := gitConfigOutput(ctx, "config", "--get", "remote."+remote+".url")
+	if err != nil {
+		return RemoteURL{}, err
+	}
+	if strings.TrimSpace(raw) == "" {
+		remotes, err := ListRemotes(ctx)
+		if err != nil {
+			return RemoteURL{}, err
+		}
+		if len(remotes) > 0 {
+			raw, err = gitConfigOutput(ctx, "config", "--get", "remote."+remotes[0]+".url")
+			if err != nil {
+				return RemoteURL{}, err
+			}
+		}
+	}
+	if strings.TrimSpace(raw) == "" {
+		root, err := MainRepoRoot(ctx)
+		if err != nil {
+			return RemoteURL{}, err
+		}
+		raw = root
+	}
+	return ParseRemoteURL(raw)
+}
@@ -0,0 +1,34 @@
+package git
+
+import "testing"
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    RemoteURL
+		wantErr bool
+	}{
+		{"https://github.com/k1LoW/git-wt.git", RemoteURL{"github.com", "k1LoW", "git-wt"}, false},
+		{"https://github.com/k1LoW/git-wt", RemoteURL{"github.com", "k1LoW", "git-wt"}, false},
+		{"https://user@github.com:8443/k1LoW/git-wt/", RemoteURL{"github.com", "k1LoW", "git-wt"}, false},
+		{"ssh://git@github.com/k1LoW/git-wt.git", RemoteURL{"github.com", "k1LoW", "git-wt"}, false},
+		{"git@github.com:k1LoW/git-wt.git", RemoteURL{"github.com", "k1LoW", "git-wt"}, false},
+		{"github.com:k1LoW/git-wt", RemoteURL{"github.com", "k1LoW", "git-wt"}, false},
+		{"git@gitlab.com:group/sub/project.git", RemoteURL{"gitlab.com", "group/sub", "project"}, false},
+		{"file:///srv/git/team/project.git", RemoteURL{"localhost", "team", "project"}, false},
+		{"/srv/git/team/project.git", RemoteURL{"localhost", "team", "project"}, false},
+		{"../project", RemoteURL{"localhost", "", "project"}, false},
+		{"https://github.com/", RemoteURL{}, true},
+		{"", RemoteURL{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRemoteURL(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRemoteURL(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRemoteURL(%q) = %+v, want %+v", tt.raw, got, tt.want)
+		}
+	}
+}
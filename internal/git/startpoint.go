@@ -0,0 +1,120 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ExpandStartPoint expands a wt.startpoint template for remote (wt.remote).
+// Supported variables:
+//   - {default}: default branch (e.g., main), see RemoteDefaultBranch
+//   - {remote}: remote (e.g., origin)
+//
+// Other revisions are used as is (e.g., @{upstream}).
+func ExpandStartPoint(ctx context.Context, tmpl, remote string) (string, error) {
+	s := strings.ReplaceAll(tmpl, "{remote}", remote)
+	if strings.Contains(s, "{default}") {
+		defaultBranch, err := RemoteDefaultBranch(ctx, remote)
+		if err != nil {
+			return "", fmt.Errorf("failed to get the default branch: %w", err)
+		}
+		s = strings.ReplaceAll(s, "{default}", defaultBranch)
+	}
+	return s, nil
+}
+
+// FetchStartPoint fetches the remote branch that startPoint refers to (e.g., origin/main, or
+// @{upstream} of the current branch), so that a new branch starts from its latest commit.
+// Git messages are written to stderr. Start points that are not remote branches are not fetched.
+func FetchStartPoint(ctx context.Context, startPoint string) error {
+	if err := checkStartPoint(startPoint); err != nil {
+		return err
+	}
+	remoteBranch, err := startPointRemoteBranch(ctx, startPoint)
+	if err != nil || remoteBranch == nil {
+		return err
+	}
+	cmd, err := gitCommand(ctx, "fetch", "--end-of-options", remoteBranch.Remote, remoteBranch.Branch)
+	if err != nil {
+		return err
+	}
+	// Output git messages to stderr so stdout only contains the path for shell integration
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", remoteBranch, err)
+	}
+	return nil
+}
+
+// startPointRemoteBranch returns the remote branch that startPoint refers to, or nil if it is not
+// a remote branch. Names of remote branches that have not been fetched yet (e.g., origin/main in a
+// fresh clone) are recognized by their remote.
+func startPointRemoteBranch(ctx context.Context, startPoint string) (*RemoteBranch, error) {
+	remotes, err := ListRemotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimPrefix(startPoint, "refs/remotes/")
+	if b := splitRemoteBranch(name, remotes); b != nil {
+		return b, nil
+	}
+
+	// Resolve symbolic names such as @{upstream}
+	cmd, err := gitCommand(ctx, "rev-parse", "--symbolic-full-name", startPoint)
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, nil //nolint:nilerr // Not a ref (e.g., a commit), nothing to fetch
+	}
+	full := strings.TrimSpace(string(out))
+	if !strings.HasPrefix(full, "refs/remotes/") {
+		return nil, nil
+	}
+	return splitRemoteBranch(strings.TrimPrefix(full, "refs/remotes/"), remotes), nil
+}
+
+// splitRemoteBranch splits name (e.g., upstream/feature/x) into the longest matching remote and a branch.
+// Branches that git would parse as options (e.g., origin/--upload-pack=...) are not recognized.
+func splitRemoteBranch(name string, remotes []string) *RemoteBranch {
+	var found *RemoteBranch
+	for _, remote := range remotes {
+		branch, ok := strings.CutPrefix(name, remote+"/")
+		if !ok || branch == "" || branch == "HEAD" || strings.HasPrefix(branch, "-") {
+			continue
+		}
+		if found == nil || len(remote) > len(found.Remote) {
+			found = &RemoteBranch{Remote: remote, Branch: branch}
+		}
+	}
+	return found
+}
+
+// ShortCommit returns the abbreviated commit hash of rev (e.g., a start point).
+func ShortCommit(ctx context.Context, rev string) (string, error) {
+	if err := checkStartPoint(rev); err != nil {
+		return "", err
+	}
+	cmd, err := gitCommand(ctx, "rev-parse", "--verify", "--quiet", "--short", "--end-of-options", rev+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %q", rev)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// checkStartPoint rejects start points that git would parse as options. Start points may come
+// from wt.startpoint in the repository config file, so they are not trusted.
+func checkStartPoint(startPoint string) error {
+	if strings.HasPrefix(startPoint, "-") {
+		return fmt.Errorf("invalid start point %q: must not start with \"-\"", startPoint)
+	}
+	return nil
+}
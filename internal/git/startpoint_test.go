@@ -0,0 +1,124 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+// newRepoWithBareRemote returns a repository whose main branch is pushed to a bare repository (origin).
+func newRepoWithBareRemote(t *testing.T) *testutil.TestRepo {
+	t.Helper()
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+	bare := filepath.Join(t.TempDir(), "remote.git")
+	repo.Git("init", "--bare", "--initial-branch=main", bare)
+	repo.Git("remote", "add", "origin", bare)
+	repo.Git("push", "-u", "origin", "main")
+	repo.Git("remote", "set-head", "origin", "--auto")
+	return repo
+}
+
+func TestExpandStartPoint(t *testing.T) {
+	repo := newRepoWithBareRemote(t)
+
+	restore := repo.Chdir()
+	defer restore()
+
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{"{remote}/{default}", "origin/main"},
+		{"origin/{default}", "origin/main"},
+		{"@{upstream}", "@{upstream}"},
+		{"v1.0", "v1.0"},
+	}
+	for _, tt := range tests {
+		got, err := ExpandStartPoint(t.Context(), tt.tmpl, DefaultRemote)
+		if err != nil {
+			t.Fatalf("ExpandStartPoint(%q) failed: %v", tt.tmpl, err)
+		}
+		if got != tt.want {
+			t.Errorf("ExpandStartPoint(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+}
+
+func TestFetchStartPoint(t *testing.T) {
+	repo := newRepoWithBareRemote(t)
+	old := repo.Git("rev-parse", "HEAD")
+
+	// Push a new commit to the remote, and make the remote-tracking branch stale
+	repo.Git("checkout", "-b", "other")
+	repo.CreateFile("new.txt", "new")
+	repo.Commit("new upstream commit")
+	latest := repo.Git("rev-parse", "HEAD")
+	repo.Git("push", "origin", "other:main")
+	repo.Git("checkout", "main")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	for _, startPoint := range []string{"origin/main", "@{upstream}"} {
+		t.Run(startPoint, func(t *testing.T) {
+			repo.Git("update-ref", "refs/remotes/origin/main", old)
+			if err := FetchStartPoint(t.Context(), startPoint); err != nil {
+				t.Fatalf("FetchStartPoint(%q) failed: %v", startPoint, err)
+			}
+			if got := repo.Git("rev-parse", "origin/main"); got != latest {
+				t.Errorf("origin/main = %s, want %s", got, latest)
+			}
+		})
+	}
+
+	t.Run("not_remote", func(t *testing.T) {
+		if err := FetchStartPoint(t.Context(), "main"); err != nil {
+			t.Errorf("FetchStartPoint(%q) failed: %v", "main", err)
+		}
+	})
+
+	t.Run("option", func(t *testing.T) {
+		marker := filepath.Join(t.TempDir(), "pwned")
+		if err := FetchStartPoint(t.Context(), "--upload-pack=touch "+marker+";"); err == nil {
+			t.Error("FetchStartPoint() should reject a start point that starts with -")
+		}
+		if err := FetchStartPoint(t.Context(), "origin/--upload-pack=touch "+marker+";"); err != nil {
+			t.Errorf("FetchStartPoint() should not fetch a branch that starts with -: %v", err)
+		}
+		if _, err := os.Stat(marker); !os.IsNotExist(err) {
+			t.Error("FetchStartPoint() should not pass the start point to git fetch as an option")
+		}
+		if _, err := ShortCommit(t.Context(), "--all"); err == nil {
+			t.Error("ShortCommit() should reject a revision that starts with -")
+		}
+	})
+}
+
+func TestSplitRemoteBranch(t *testing.T) {
+	remotes := []string{"origin", "origin/fork"}
+	tests := []struct {
+		name       string
+		wantRemote string
+		wantBranch string
+	}{
+		{"origin/main", "origin", "main"},
+		{"origin/feature/x", "origin", "feature/x"},
+		{"origin/fork/main", "origin/fork", "main"},
+		{"origin/HEAD", "", ""},
+		{"upstream/main", "", ""},
+		{"origin/--upload-pack=touch pwned;", "", ""},
+	}
+	for _, tt := range tests {
+		var got RemoteBranch
+		if b := splitRemoteBranch(tt.name, remotes); b != nil {
+			got = *b
+		}
+		if got.Remote != tt.wantRemote || got.Branch != tt.wantBranch {
+			t.Errorf("splitRemoteBranch(%q) = %+v, want %s %s", tt.name, got, tt.wantRemote, tt.wantBranch)
+		}
+	}
+}
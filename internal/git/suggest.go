@@ -0,0 +1,86 @@
+package git
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// maxSuggestions is the largest number of names returned by SuggestNames.
+const maxSuggestions = 3
+
+// SuggestNames returns the names closest to name (e.g., for a typo), closest first.
+// Candidates are the branches and directory names of worktrees, local branches and remote branches.
+func SuggestNames(ctx context.Context, name string) ([]string, error) {
+	var candidates []string
+	worktrees, err := ListWorktrees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, wt := range worktrees {
+		if wt.Branch != "" && wt.Branch != DetachedMarker {
+			candidates = append(candidates, wt.Branch)
+		}
+		dirName, err := WorktreeDirName(ctx, &wt)
+		if err == nil && !strings.HasPrefix(dirName, "..") {
+			candidates = append(candidates, filepath.ToSlash(dirName))
+		}
+	}
+
+	branches, err := ListBranches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+	candidates = append(candidates, branches...)
+
+	// Remote branches are given without the remote name (see BranchExists)
+	cmd, err := gitCommand(ctx, "for-each-ref", "--format=%(refname:lstrip=3)", "refs/remotes/")
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote branches: %w", err)
+	}
+	for _, b := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if b != "" && b != "HEAD" {
+			candidates = append(candidates, b)
+		}
+	}
+
+	return rankSuggestions(name, candidates), nil
+}
+
+// rankSuggestions returns up to maxSuggestions candidates closest to name by edit distance
+// (case-insensitive), closest first. Candidates more than a third of the length of name away
+// (at least 2 edits are always allowed) are dropped.
+func rankSuggestions(name string, candidates []string) []string {
+	maxDistance := max(2, len(name)/3)
+	type suggestion struct {
+		name     string
+		distance int
+	}
+	var suggestions []suggestion
+	seen := map[string]struct{}{}
+	for _, c := range candidates {
+		if _, ok := seen[c]; ok || c == name {
+			continue
+		}
+		seen[c] = struct{}{}
+		if d := editDistance(strings.ToLower(name), strings.ToLower(c)); d <= maxDistance {
+			suggestions = append(suggestions, suggestion{c, d})
+		}
+	}
+	slices.SortFunc(suggestions, func(a, b suggestion) int {
+		return cmp.Or(cmp.Compare(a.distance, b.distance), strings.Compare(a.name, b.name))
+	})
+
+	var names []string
+	for _, s := range suggestions[:min(len(suggestions), maxSuggestions)] {
+		names = append(names, s.name)
+	}
+	return names
+}
@@ -0,0 +1,65 @@
+package git
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestRankSuggestions(t *testing.T) {
+	candidates := []string{"main", "feature-x", "feature-y", "Feature-X2", "bugfix/login", "feature-x"}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"feautre-x", []string{"feature-x", "Feature-X2", "feature-y"}},
+		{"bugfix/logn", []string{"bugfix/login"}},
+		{"mian", []string{"main"}},
+		{"something-else", nil},
+		{"main", nil},
+	}
+	for _, tt := range tests {
+		if got := rankSuggestions(tt.name, candidates); !slices.Equal(got, tt.want) {
+			t.Errorf("rankSuggestions(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestNames(t *testing.T) {
+	remote := testutil.NewTestRepo(t)
+	remote.CreateFile("README.md", "# Test")
+	remote.Commit("initial commit")
+	remote.Git("branch", "release-1.0")
+
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+	repo.Git("remote", "add", "origin", remote.Root)
+	repo.Git("fetch", "origin")
+	repo.Git("branch", "feature-login")
+	repo.Git("worktree", "add", "-b", "fix/crash", repo.Path(".wt/crash-fix"))
+
+	restore := repo.Chdir()
+	defer restore()
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"feature-logn", []string{"feature-login"}},
+		{"fix/crahs", []string{"fix/crash"}},
+		{"crash-fxi", []string{"crash-fix"}},
+		{"release-1.1", []string{"release-1.0"}},
+		{"unrelated-name", nil},
+	}
+	for _, tt := range tests {
+		got, err := SuggestNames(t.Context(), tt.name)
+		if err != nil {
+			t.Fatalf("SuggestNames(%q) failed: %v", tt.name, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("SuggestNames(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
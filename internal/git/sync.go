@@ -0,0 +1,210 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// SyncStatus is the state of a copied file in a target worktree compared to the source worktree.
+type SyncStatus string
+
+const (
+	// SyncUpToDate means the file in the target has the same content as the source.
+	SyncUpToDate SyncStatus = "up-to-date"
+	// SyncMissing means the file does not exist in the target.
+	SyncMissing SyncStatus = "missing"
+	// SyncDrifted means the source file changed since it was copied to the target.
+	SyncDrifted SyncStatus = "drifted"
+	// SyncModified means the file was modified in the target after it was copied,
+	// or it differs from the source and was not recorded when copied.
+	SyncModified SyncStatus = "modified"
+)
+
+// SyncOptions configures SyncWorktree.
+type SyncOptions struct {
+	Check bool // Only report status, do not write anything
+	Force bool // Overwrite files modified in the target worktree
+	// BaseDir is the worktree base directory. It is excluded from copying when it is inside srcRoot.
+	BaseDir string
+}
+
+// SyncResult is the sync status of a single path.
+type SyncResult struct {
+	Path   string
+	Status SyncStatus
+	Synced bool // Whether the path was (re-)copied or linked
+}
+
+// SyncWorktree re-applies the CopyOptions selection from srcRoot into the existing worktree at dstRoot.
+// Files whose content differs from the source are reported as drifted when the target still has the
+// content recorded when it was copied (see copyRecord), or as modified otherwise (i.e., it was changed
+// in the target after being copied, or its copy was not recorded).
+// Missing and drifted files are copied unless Check is set; modified files are only overwritten with Force.
+func SyncWorktree(ctx context.Context, srcRoot, dstRoot string, copyOpts CopyOptions, opts SyncOptions) ([]SyncResult, error) {
+	// Exclude basedir and other worktrees nested in the source to prevent circular copying
+	if opts.BaseDir != "" {
+		if _, ok := relativeWithin(srcRoot, opts.BaseDir); ok {
+			copyOpts.ExcludeDirs = append(copyOpts.ExcludeDirs, opts.BaseDir)
+		}
+	}
+	worktrees, err := ListWorktrees(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	for _, wt := range worktrees {
+		if rel, ok := relativeWithin(srcRoot, wt.Path); ok && rel != "." {
+			copyOpts.ExcludeDirs = append(copyOpts.ExcludeDirs, wt.Path)
+		}
+	}
+
+	plan, err := planCopy(ctx, srcRoot, copyOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := openCopyRecord(ctx, dstRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SyncResult
+	noCopyMatcher := newPatternMatcher(copyOpts.NoCopy)
+	for _, l := range plan.links {
+		r := SyncResult{Path: l.path, Status: SyncUpToDate}
+		if _, err := os.Lstat(filepath.Join(dstRoot, l.path)); err != nil {
+			if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to stat %s: %w", l.path, err)
+			}
+			r.Status = SyncMissing
+		}
+		if r.Status == SyncMissing && !opts.Check {
+			if err := linkPath(srcRoot, dstRoot, l, noCopyMatcher, copyOpts); err != nil {
+				return nil, fmt.Errorf("failed to link %s: %w", l.path, err)
+			}
+			r.Synced = true
+		}
+		results = append(results, r)
+	}
+
+	for _, file := range plan.files {
+		r, err := syncFile(srcRoot, dstRoot, file, plan.sources[file], record, copyOpts, opts)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+
+	// Files overlaid from the copy source directory
+	for _, file := range plan.overlay {
+		r, err := syncFile(copyOpts.CopySource, dstRoot, file, sourceCopySource, record, copyOpts, opts)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+
+	if !opts.Check {
+		if err := record.save(); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// syncFile copies the file at rel from srcRoot into dstRoot if it is missing or out of date,
+// and records the content of copied and up-to-date files.
+// source is the option that selected the file, reported in verbose output.
+func syncFile(srcRoot, dstRoot, rel, source string, record *copyRecord, copyOpts CopyOptions, opts SyncOptions) (SyncResult, error) {
+	status, err := syncStatus(srcRoot, dstRoot, rel, record, copyOpts)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	r := SyncResult{Path: rel, Status: status}
+	if opts.Check {
+		return r, nil
+	}
+	if status == SyncMissing || status == SyncDrifted || (status == SyncModified && opts.Force) {
+		dst := filepath.Join(dstRoot, rel)
+		// Remove the existing file first so that hardlinks and symlinks in the target
+		// are replaced instead of written through.
+		if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+			return SyncResult{}, fmt.Errorf("failed to remove %s: %w", rel, err)
+		}
+		copyPath(srcRoot, dstRoot, rel, source, copyOpts)
+		r.Synced = true
+	}
+	if r.Synced || status == SyncUpToDate {
+		record.add(dstRoot, rel)
+	}
+	return r, nil
+}
+
+// syncStatus compares the file at rel in srcRoot with the one in dstRoot.
+// A target that differs from the source is drifted only if it still has the content recorded
+// when it was copied. Symlinks are only checked for existence unless FollowSymlinks is set.
+func syncStatus(srcRoot, dstRoot, rel string, record *copyRecord, copyOpts CopyOptions) (SyncStatus, error) {
+	src := filepath.Join(srcRoot, rel)
+	dst := filepath.Join(dstRoot, rel)
+
+	dstInfo, err := os.Lstat(dst)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return SyncMissing, nil
+		}
+		return "", fmt.Errorf("failed to stat %s: %w", rel, err)
+	}
+
+	srcInfo, err := os.Lstat(src)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat %s: %w", rel, err)
+	}
+	if srcInfo.Mode()&os.ModeSymlink != 0 {
+		if !copyOpts.FollowSymlinks {
+			return SyncUpToDate, nil
+		}
+		if _, err := os.Stat(src); err != nil {
+			return "", fmt.Errorf("failed to stat %s: %w", rel, err)
+		}
+	}
+	if dstInfo.Mode()&os.ModeSymlink != 0 {
+		if _, err := os.Stat(dst); err != nil {
+			// Dangling symlink in the target
+			return SyncModified, nil
+		}
+	}
+
+	srcHash, err := fileHash(src)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", rel, err)
+	}
+	dstHash, err := fileHash(dst)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", rel, err)
+	}
+	if bytes.Equal(srcHash, dstHash) {
+		return SyncUpToDate, nil
+	}
+	if record.matches(rel, dstHash) {
+		return SyncDrifted, nil
+	}
+	return SyncModified, nil
+}
+
+// fileHash returns the SHA-256 hash of the file content.
+func fileHash(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
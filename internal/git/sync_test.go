@@ -0,0 +1,247 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func setupSyncRepo(t *testing.T) (*testutil.TestRepo, string) {
+	t.Helper()
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", ".env\n*.local\n")
+	repo.Commit("initial commit")
+
+	repo.CreateFile(".env", "SECRET=old")
+	repo.CreateFile("settings.local", "local")
+
+	// The target is a worktree, so copies are recorded in its git directory
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	repo.Git("worktree", "add", "--detach", dstDir)
+	return repo, dstDir
+}
+
+func syncStatuses(results []SyncResult) map[string]SyncStatus {
+	statuses := make(map[string]SyncStatus)
+	for _, r := range results {
+		statuses[r.Path] = r.Status
+	}
+	return statuses
+}
+
+func TestSyncWorktree(t *testing.T) {
+	repo, dstDir := setupSyncRepo(t)
+	restore := repo.Chdir()
+	defer restore()
+
+	opts := CopyOptions{CopyIgnored: true}
+	if err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts); err != nil {
+		t.Fatalf("CopyFilesToWorktree failed: %v", err)
+	}
+
+	// Rotate the secret in the source and remove a copy from the target
+	repo.CreateFile(".env", "SECRET=new")
+	if err := os.Remove(filepath.Join(dstDir, "settings.local")); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+
+	results, err := SyncWorktree(t.Context(), repo.Root, dstDir, opts, SyncOptions{Check: true})
+	if err != nil {
+		t.Fatalf("SyncWorktree failed: %v", err)
+	}
+	statuses := syncStatuses(results)
+	if statuses[".env"] != SyncDrifted {
+		t.Errorf(".env status = %q, want %q", statuses[".env"], SyncDrifted)
+	}
+	if statuses["settings.local"] != SyncMissing {
+		t.Errorf("settings.local status = %q, want %q", statuses["settings.local"], SyncMissing)
+	}
+
+	// Check mode must not write anything
+	content, err := os.ReadFile(filepath.Join(dstDir, ".env"))
+	if err != nil {
+		t.Fatalf("failed to read .env: %v", err)
+	}
+	if string(content) != "SECRET=old" {
+		t.Errorf(".env content = %q, want %q", string(content), "SECRET=old")
+	}
+
+	results, err = SyncWorktree(t.Context(), repo.Root, dstDir, opts, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncWorktree failed: %v", err)
+	}
+	for _, r := range results {
+		if r.Status != SyncUpToDate && !r.Synced {
+			t.Errorf("%s (%s) was not synced", r.Path, r.Status)
+		}
+	}
+	content, err = os.ReadFile(filepath.Join(dstDir, ".env"))
+	if err != nil {
+		t.Fatalf("failed to read .env: %v", err)
+	}
+	if string(content) != "SECRET=new" {
+		t.Errorf(".env content = %q, want %q", string(content), "SECRET=new")
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, "settings.local")); err != nil {
+		t.Errorf("settings.local was not restored: %v", err)
+	}
+
+	// Everything is up to date afterwards
+	results, err = SyncWorktree(t.Context(), repo.Root, dstDir, opts, SyncOptions{Check: true})
+	if err != nil {
+		t.Fatalf("SyncWorktree failed: %v", err)
+	}
+	for path, status := range syncStatuses(results) {
+		if status != SyncUpToDate {
+			t.Errorf("%s status = %q, want %q", path, status, SyncUpToDate)
+		}
+	}
+}
+
+func TestSyncWorktree_ModifiedRequiresForce(t *testing.T) {
+	repo, dstDir := setupSyncRepo(t)
+	restore := repo.Chdir()
+	defer restore()
+
+	opts := CopyOptions{CopyIgnored: true}
+	if err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts); err != nil {
+		t.Fatalf("CopyFilesToWorktree failed: %v", err)
+	}
+
+	// Edit the copy in the target worktree and change the source as well
+	dstEnv := filepath.Join(dstDir, ".env")
+	if err := os.WriteFile(dstEnv, []byte("SECRET=mine"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	repo.CreateFile(".env", "SECRET=new")
+
+	results, err := SyncWorktree(t.Context(), repo.Root, dstDir, opts, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncWorktree failed: %v", err)
+	}
+	if got := syncStatuses(results)[".env"]; got != SyncModified {
+		t.Errorf(".env status = %q, want %q", got, SyncModified)
+	}
+	content, err := os.ReadFile(dstEnv)
+	if err != nil {
+		t.Fatalf("failed to read .env: %v", err)
+	}
+	if string(content) != "SECRET=mine" {
+		t.Errorf(".env was overwritten without force: %q", string(content))
+	}
+
+	if _, err := SyncWorktree(t.Context(), repo.Root, dstDir, opts, SyncOptions{Force: true}); err != nil {
+		t.Fatalf("SyncWorktree failed: %v", err)
+	}
+	content, err = os.ReadFile(dstEnv)
+	if err != nil {
+		t.Fatalf("failed to read .env: %v", err)
+	}
+	if string(content) != "SECRET=new" {
+		t.Errorf(".env content = %q, want %q", string(content), "SECRET=new")
+	}
+}
+
+func TestSyncWorktree_UnrecordedCopyIsModified(t *testing.T) {
+	repo, dstDir := setupSyncRepo(t)
+	restore := repo.Chdir()
+	defer restore()
+
+	// A file that differs from the source without a recorded copy (e.g., created in the target)
+	// may have local changes, so it is not overwritten without force
+	if err := os.WriteFile(filepath.Join(dstDir, ".env"), []byte("SECRET=mine"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	opts := CopyOptions{CopyIgnored: true}
+	results, err := SyncWorktree(t.Context(), repo.Root, dstDir, opts, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncWorktree failed: %v", err)
+	}
+	statuses := syncStatuses(results)
+	if statuses[".env"] != SyncModified {
+		t.Errorf(".env status = %q, want %q", statuses[".env"], SyncModified)
+	}
+	if statuses["settings.local"] != SyncMissing {
+		t.Errorf("settings.local status = %q, want %q", statuses["settings.local"], SyncMissing)
+	}
+
+	// The synced copy of settings.local is recorded, so a later change in the source drifts
+	repo.CreateFile("settings.local", "changed")
+	results, err = SyncWorktree(t.Context(), repo.Root, dstDir, opts, SyncOptions{Check: true})
+	if err != nil {
+		t.Fatalf("SyncWorktree failed: %v", err)
+	}
+	if got := syncStatuses(results)["settings.local"]; got != SyncDrifted {
+		t.Errorf("settings.local status = %q, want %q", got, SyncDrifted)
+	}
+}
+
+func TestSyncWorktree_RelinksMissingLinks(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", "node_modules/\n")
+	repo.Commit("initial commit")
+	repo.CreateFile("node_modules/pkg/index.js", "module.exports = {}")
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	opts := CopyOptions{Link: []string{"node_modules/"}}
+	results, err := SyncWorktree(t.Context(), repo.Root, dstDir, opts, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncWorktree failed: %v", err)
+	}
+	if got := syncStatuses(results)["node_modules"]; got != SyncMissing {
+		t.Errorf("node_modules status = %q, want %q", got, SyncMissing)
+	}
+	info, err := os.Lstat(filepath.Join(dstDir, "node_modules"))
+	if err != nil {
+		t.Fatalf("node_modules was not linked: %v", err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Error("node_modules is not a symlink")
+	}
+}
+
+func TestSyncWorktree_CopySource(t *testing.T) {
+	repo, dstDir := setupSyncRepo(t)
+	restore := repo.Chdir()
+	defer restore()
+
+	copySource := t.TempDir()
+	if err := os.WriteFile(filepath.Join(copySource, ".env"), []byte("SECRET=external"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	opts := CopyOptions{CopyIgnored: true, CopySource: copySource}
+	if _, err := SyncWorktree(t.Context(), repo.Root, dstDir, opts, SyncOptions{}); err != nil {
+		t.Fatalf("SyncWorktree failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dstDir, ".env"))
+	if err != nil {
+		t.Fatalf("failed to read .env: %v", err)
+	}
+	if string(content) != "SECRET=external" {
+		t.Errorf(".env content = %q, want %q", string(content), "SECRET=external")
+	}
+
+	// The copy source wins over the source worktree, so a second sync has nothing to do
+	results, err := SyncWorktree(t.Context(), repo.Root, dstDir, opts, SyncOptions{Check: true})
+	if err != nil {
+		t.Fatalf("SyncWorktree failed: %v", err)
+	}
+	for path, status := range syncStatuses(results) {
+		if status != SyncUpToDate {
+			t.Errorf("%s status = %q, want %q", path, status, SyncUpToDate)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// TemplateData is the data available to files rendered from wt.template patterns.
+type TemplateData struct {
+	Branch          string // Branch name (e.g., feature/login)
+	BranchSanitized string // Branch name with characters other than letters, digits and underscores replaced by "_" (e.g., feature_login)
+	DirName         string // Worktree directory name
+	Index           int    // Position of the worktree in "git worktree list" (the main worktree is 0)
+	RepoName        string // Repository name
+	Ports           []int  // Ports allocated to the worktree by the port registry (empty if none)
+}
+
+var unsafeBranchChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// templateFuncs are the functions available to wt.template files.
+var templateFuncs = template.FuncMap{
+	"add": func(a, b int) int { return a + b },
+}
+
+// NewTemplateData returns the template data for the worktree at path checked out on branch.
+func NewTemplateData(ctx context.Context, path, branch string) (*TemplateData, error) {
+	repoName, err := RepoName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	worktrees, err := ListWorktrees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index := len(worktrees)
+	for i, wt := range worktrees {
+		if wt.Path == path {
+			index = i
+			break
+		}
+	}
+	registry, err := OpenPortRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	block, _, err := registry.Lookup(path)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateData{
+		Branch:          branch,
+		BranchSanitized: unsafeBranchChars.ReplaceAllString(branch, "_"),
+		DirName:         filepath.Base(path),
+		Index:           index,
+		RepoName:        repoName,
+		Ports:           block.Ports(),
+	}, nil
+}
+
+// templateOutputPath returns the path a template file is rendered to.
+// The ".tmpl" suffix and then the ".wt" suffix are removed (e.g., ".env.wt.tmpl" -> ".env").
+func templateOutputPath(rel string) string {
+	out := strings.TrimSuffix(rel, ".tmpl")
+	out = strings.TrimSuffix(out, ".wt")
+	if out == "" || strings.HasSuffix(out, string(filepath.Separator)) {
+		return rel
+	}
+	return out
+}
+
+// renderTemplate renders the template file at rel in srcRoot into dstRoot and returns the output path.
+// The file mode of the template is preserved.
+func renderTemplate(srcRoot, dstRoot, rel string, data *TemplateData) (string, error) {
+	src := filepath.Join(srcRoot, rel)
+	info, err := os.Stat(src)
+	if err != nil {
+		return "", err
+	}
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := template.New(rel).Funcs(templateFuncs).Option("missingkey=error").Parse(string(b))
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	out := templateOutputPath(rel)
+	dst := filepath.Join(dstRoot, out)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", err
+	}
+	// Replace instead of writing through a copied hardlink or symlink
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err := os.WriteFile(dst, buf.Bytes(), info.Mode().Perm()); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// listFilesMatchingTemplatePatterns returns tracked, untracked and ignored files matching the patterns.
+func listFilesMatchingTemplatePatterns(ctx context.Context, root string, patterns []string) ([]string, error) {
+	cmd, err := gitCommand(ctx, "ls-files", "--cached")
+	if err != nil {
+		return nil, err
+	}
+	cmd.Dir = root
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracked files: %w", err)
+	}
+	allFiles := parseFileList(string(out))
+
+	others, err := listFilesMatchingCopyPatterns(ctx, root, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	matcher := newPatternMatcher(patterns)
+	var result []string
+	for _, file := range allFiles {
+		if matcher.Match(splitPath(file), false) {
+			result = append(result, file)
+		}
+	}
+	return append(result, others...), nil
+}
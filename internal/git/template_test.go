@@ -0,0 +1,107 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+func TestTemplateOutputPath(t *testing.T) {
+	tests := []struct {
+		rel  string
+		want string
+	}{
+		{".env.wt.tmpl", ".env"},
+		{"config/app.json.tmpl", filepath.Join("config", "app.json")},
+		{".env.wt", ".env"},
+		{"settings.tmpl.local", "settings.tmpl.local"},
+		{".tmpl", ".tmpl"},
+	}
+	for _, tt := range tests {
+		if got := templateOutputPath(filepath.FromSlash(tt.rel)); got != tt.want {
+			t.Errorf("templateOutputPath(%q) = %q, want %q", tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestNewTemplateData(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	wtPath := filepath.Join(repo.ParentDir(), "feature-login")
+	repo.Git("worktree", "add", "-b", "feature/login", wtPath)
+
+	restore := repo.Chdir()
+	defer restore()
+
+	registry, err := OpenPortRegistry(t.Context())
+	if err != nil {
+		t.Fatalf("OpenPortRegistry failed: %v", err)
+	}
+	if _, err := registry.Allocate(wtPath, 2, 30000); err != nil {
+		t.Fatalf("Allocate failed: %v", err)
+	}
+
+	data, err := NewTemplateData(t.Context(), wtPath, "feature/login")
+	if err != nil {
+		t.Fatalf("NewTemplateData failed: %v", err)
+	}
+	want := TemplateData{
+		Branch:          "feature/login",
+		BranchSanitized: "feature_login",
+		DirName:         "feature-login",
+		Index:           1,
+		RepoName:        filepath.Base(repo.Root),
+		Ports:           []int{30000, 30001},
+	}
+	if !reflect.DeepEqual(*data, want) {
+		t.Errorf("NewTemplateData() = %+v, want %+v", *data, want)
+	}
+}
+
+func TestCopyFilesToWorktree_Template(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", ".env\n")
+	repo.CreateFile(".env.wt.tmpl", "DATABASE_NAME=app_{{.BranchSanitized}}\nPORT={{add 3000 .Index}}\n")
+	repo.Commit("initial commit")
+
+	// The ignored .env would be copied, but the rendered template replaces it
+	repo.CreateFile(".env", "DATABASE_NAME=app_main\nPORT=3000\n")
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	opts := CopyOptions{
+		CopyIgnored:  true,
+		Template:     []string{"*.wt.tmpl"},
+		TemplateData: &TemplateData{Branch: "feature/x", BranchSanitized: "feature_x", Index: 2},
+	}
+	if err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts); err != nil {
+		t.Fatalf("CopyFilesToWorktree failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dstDir, ".env"))
+	if err != nil {
+		t.Fatalf("failed to read .env: %v", err)
+	}
+	want := "DATABASE_NAME=app_feature_x\nPORT=3002\n"
+	if string(content) != want {
+		t.Errorf(".env content = %q, want %q", string(content), want)
+	}
+
+	// Templates with unknown fields fail to render
+	repo.CreateFile(".env.wt.tmpl", "{{.Unknown}}")
+	if err := CopyFilesToWorktree(t.Context(), repo.Root, dstDir, opts); err == nil {
+		t.Error("CopyFilesToWorktree should fail for an invalid template")
+	}
+}
@@ -0,0 +1,304 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// DefaultWatchDebounce is the default delay between the last change of a file and mirroring it.
+const DefaultWatchDebounce = 300 * time.Millisecond
+
+// WatchOptions configures WatchWorktrees.
+type WatchOptions struct {
+	// Targets are the worktree roots to mirror changes into.
+	// If empty, all worktrees except the source are used, including worktrees created while watching.
+	Targets []string
+	// Debounce is the delay between the last change and mirroring. Zero means DefaultWatchDebounce.
+	Debounce time.Duration
+	// BaseDir is the worktree base directory. It is not watched when it is inside srcRoot.
+	BaseDir string
+	// Log receives a line for every mirrored file. nil disables logging.
+	Log io.Writer
+}
+
+// WatchWorktrees watches srcRoot and mirrors changes to files matching the Copy patterns
+// into the target worktrees until ctx is canceled.
+// Files matching NoCopy patterns and tracked files are never mirrored. Deletions are not mirrored.
+// A target whose file already has the same content is left untouched, which prevents mirroring
+// loops when worktrees watch each other.
+func WatchWorktrees(ctx context.Context, srcRoot string, copyOpts CopyOptions, opts WatchOptions) error {
+	copyMatcher := newPatternMatcher(copyOpts.Copy)
+	if copyMatcher == nil {
+		return errors.New("no copy patterns to watch (set wt.copy or use --copy)")
+	}
+	noCopyMatcher := newPatternMatcher(copyOpts.NoCopy)
+	debounce := opts.Debounce
+	if debounce == 0 {
+		debounce = DefaultWatchDebounce
+	}
+
+	// Never watch basedir or other worktrees nested in the source
+	excludeDirs := copyOpts.ExcludeDirs
+	if opts.BaseDir != "" {
+		if _, ok := relativeWithin(srcRoot, opts.BaseDir); ok {
+			excludeDirs = append(excludeDirs, opts.BaseDir)
+		}
+	}
+	worktrees, err := ListWorktrees(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	for _, wt := range worktrees {
+		if rel, ok := relativeWithin(srcRoot, wt.Path); ok && rel != "." {
+			excludeDirs = append(excludeDirs, wt.Path)
+		}
+	}
+
+	// Whole ignored directories (e.g., node_modules/) are only watched when a copy pattern
+	// matches the directory or is anchored inside it, to keep the number of watches small.
+	ignored, err := listIgnoredEntries(ctx, srcRoot)
+	if err != nil {
+		return err
+	}
+	for _, entry := range ignored {
+		dir, ok := strings.CutSuffix(entry, "/")
+		if !ok || copyPatternMayMatchIn(copyMatcher, copyOpts.Copy, dir) {
+			continue
+		}
+		excludeDirs = append(excludeDirs, filepath.Join(srcRoot, dir))
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	w := &worktreeWatcher{
+		srcRoot:       srcRoot,
+		copyOpts:      copyOpts,
+		opts:          opts,
+		watcher:       watcher,
+		copyMatcher:   copyMatcher,
+		noCopyMatcher: noCopyMatcher,
+		excludeDirs:   excludeDirs,
+	}
+	if err := w.addDir(srcRoot); err != nil {
+		return err
+	}
+
+	pending := make(map[string]struct{})
+	var flush <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if ev.Has(fsnotify.Remove) || ev.Has(fsnotify.Rename) {
+				continue
+			}
+			info, err := os.Lstat(ev.Name)
+			if err != nil {
+				continue
+			}
+			if info.IsDir() {
+				if ev.Has(fsnotify.Create) {
+					// Watch new directories and mirror the files already created inside them
+					if err := w.addDir(ev.Name); err != nil {
+						w.logf("skip %s: %v\n", ev.Name, err)
+					}
+					for _, rel := range w.listMatchingFiles(ev.Name) {
+						pending[rel] = struct{}{}
+					}
+					flush = time.After(debounce)
+				}
+				continue
+			}
+			rel, err := filepath.Rel(srcRoot, ev.Name)
+			if err != nil || !w.matches(rel) {
+				continue
+			}
+			pending[rel] = struct{}{}
+			flush = time.After(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			w.logf("watch error: %v\n", err)
+		case <-flush:
+			targets, err := w.targets(ctx)
+			if err != nil {
+				return err
+			}
+			for rel := range pending {
+				w.mirror(ctx, rel, targets)
+			}
+			pending = make(map[string]struct{})
+			flush = nil
+		}
+	}
+}
+
+// worktreeWatcher holds the state of WatchWorktrees.
+type worktreeWatcher struct {
+	srcRoot       string
+	copyOpts      CopyOptions
+	opts          WatchOptions
+	watcher       *fsnotify.Watcher
+	copyMatcher   gitignore.Matcher
+	noCopyMatcher gitignore.Matcher
+	excludeDirs   []string
+}
+
+// addDir watches dir and its subdirectories, skipping .git, excluded and nocopy directories.
+func (w *worktreeWatcher) addDir(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() && path != dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if d.Name() == ".git" || isExcludedDir(path, w.excludeDirs) {
+			return filepath.SkipDir
+		}
+		if rel, err := filepath.Rel(w.srcRoot, path); err == nil && rel != "." {
+			if w.noCopyMatcher != nil && w.noCopyMatcher.Match(splitPath(rel), true) {
+				return filepath.SkipDir
+			}
+		}
+		if err := w.watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// listMatchingFiles returns the files in dir matching the copy patterns, relative to the source root.
+func (w *worktreeWatcher) listMatchingFiles(dir string) []string {
+	var files []string
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if rel, err := filepath.Rel(w.srcRoot, path); err == nil && w.matches(rel) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files
+}
+
+// matches reports whether the file at rel should be mirrored.
+func (w *worktreeWatcher) matches(rel string) bool {
+	if !w.copyMatcher.Match(splitPath(rel), false) {
+		return false
+	}
+	return w.noCopyMatcher == nil || !w.noCopyMatcher.Match(splitPath(rel), false)
+}
+
+// targets returns the worktree roots to mirror into.
+func (w *worktreeWatcher) targets(ctx context.Context) ([]string, error) {
+	if len(w.opts.Targets) > 0 {
+		return w.opts.Targets, nil
+	}
+	worktrees, err := ListWorktrees(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	var targets []string
+	for _, wt := range worktrees {
+		if wt.Bare || wt.Path == w.srcRoot {
+			continue
+		}
+		targets = append(targets, wt.Path)
+	}
+	return targets, nil
+}
+
+// mirror copies the file at rel into every target whose content differs.
+func (w *worktreeWatcher) mirror(ctx context.Context, rel string, targets []string) {
+	src := filepath.Join(w.srcRoot, rel)
+	if _, err := os.Lstat(src); err != nil {
+		// Removed before the debounce elapsed
+		return
+	}
+	tracked, err := isTracked(ctx, w.srcRoot, rel)
+	if err != nil || tracked {
+		return
+	}
+	srcHash, err := fileHash(src)
+	if err != nil {
+		w.logf("skip %s: %v\n", rel, err)
+		return
+	}
+	for _, target := range targets {
+		dst := filepath.Join(target, rel)
+		if dstHash, err := fileHash(dst); err == nil && bytes.Equal(srcHash, dstHash) {
+			continue
+		}
+		// Replace instead of writing through hardlinks and symlinks in the target
+		if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+			w.logf("skip %s: %v\n", dst, err)
+			continue
+		}
+		copyPath(w.srcRoot, target, rel, sourceCopy, CopyOptions{FollowSymlinks: w.copyOpts.FollowSymlinks})
+		w.logf("mirror %s -> %s\n", rel, target)
+		// Mirrored content is a copy, so sync does not report it as modified in the target
+		if err := recordCopies(ctx, target, []string{rel}); err != nil {
+			w.logf("skip recording %s: %v\n", dst, err)
+		}
+	}
+}
+
+func (w *worktreeWatcher) logf(format string, args ...any) {
+	if w.opts.Log != nil {
+		fmt.Fprintf(w.opts.Log, format, args...)
+	}
+}
+
+// copyPatternMayMatchIn reports whether a copy pattern matches the directory dir itself,
+// or is anchored to a path inside it (e.g., "/node_modules/.cache/config.json").
+func copyPatternMayMatchIn(matcher gitignore.Matcher, patterns []string, dir string) bool {
+	if matcher.Match(splitPath(dir), true) {
+		return true
+	}
+	prefix := filepath.ToSlash(dir) + "/"
+	for _, p := range patterns {
+		if strings.HasPrefix(strings.TrimPrefix(p, "/"), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTracked reports whether the file at rel is tracked in the worktree at root.
+func isTracked(ctx context.Context, root, rel string) (bool, error) {
+	cmd, err := gitCommand(ctx, "ls-files", "--", rel)
+	if err != nil {
+		return false, err
+	}
+	cmd.Dir = root
+	out, err := cmd.Output()
+	if err != nil {
+		return false, err
+	}
+	return len(bytes.TrimSpace(out)) > 0, nil
+}
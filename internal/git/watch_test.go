@@ -0,0 +1,150 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/git-wt/testutil"
+)
+
+// startWatch runs WatchWorktrees in the background until the test ends.
+func startWatch(t *testing.T, srcRoot string, copyOpts CopyOptions, opts WatchOptions) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() {
+		done <- WatchWorktrees(ctx, srcRoot, copyOpts, opts)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("WatchWorktrees failed: %v", err)
+		}
+	})
+}
+
+// waitForContent rewrites src until dst has the given content, since the watcher
+// may not be ready when the file is first written.
+func waitForContent(t *testing.T, src, dst, content string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if err := os.WriteFile(src, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+		if got, err := os.ReadFile(dst); err == nil && string(got) == content {
+			return
+		}
+	}
+	t.Fatalf("%s was not mirrored to %s", src, dst)
+}
+
+func TestWatchWorktrees(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", "*.local\n")
+	repo.Commit("initial commit")
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	copyOpts := CopyOptions{Copy: []string{"*.local"}, NoCopy: []string{"secret.local"}}
+	startWatch(t, repo.Root, copyOpts, WatchOptions{Targets: []string{dstDir}, Debounce: 10 * time.Millisecond})
+
+	waitForContent(t, repo.Path(".env.local"), filepath.Join(dstDir, ".env.local"), "PORT=3000")
+	waitForContent(t, repo.Path(".env.local"), filepath.Join(dstDir, ".env.local"), "PORT=4000")
+
+	// Files in new directories are mirrored
+	if err := os.MkdirAll(repo.Path("config"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	waitForContent(t, repo.Path("config/app.local"), filepath.Join(dstDir, "config", "app.local"), "app")
+
+	// Files matching NoCopy patterns are never mirrored
+	if err := os.WriteFile(repo.Path("secret.local"), []byte("secret"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if _, err := os.Stat(filepath.Join(dstDir, "secret.local")); !os.IsNotExist(err) {
+		t.Error("secret.local should not be mirrored")
+	}
+}
+
+func TestWatchWorktrees_SkipsSameContent(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.CreateFile(".gitignore", "*.local\n")
+	repo.Commit("initial commit")
+
+	dstDir := filepath.Join(repo.ParentDir(), "dst")
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		t.Fatalf("failed to create dst dir: %v", err)
+	}
+
+	restore := repo.Chdir()
+	defer restore()
+
+	startWatch(t, repo.Root, CopyOptions{Copy: []string{"*.local"}}, WatchOptions{Targets: []string{dstDir}, Debounce: 10 * time.Millisecond})
+	waitForContent(t, repo.Path(".env.local"), filepath.Join(dstDir, ".env.local"), "PORT=3000")
+
+	// A target with the same content is not rewritten
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	dst := filepath.Join(dstDir, ".env.local")
+	if err := os.Chtimes(dst, old, old); err != nil {
+		t.Fatalf("failed to set file time: %v", err)
+	}
+	if err := os.WriteFile(repo.Path(".env.local"), []byte("PORT=3000"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	info, err := os.Stat(dst)
+	if err != nil {
+		t.Fatalf("failed to stat file: %v", err)
+	}
+	if !info.ModTime().Equal(old) {
+		t.Errorf("target with the same content was rewritten")
+	}
+}
+
+func TestWatchWorktrees_NoPatterns(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	if err := WatchWorktrees(t.Context(), repo.Root, CopyOptions{}, WatchOptions{}); err == nil {
+		t.Error("WatchWorktrees should fail without copy patterns")
+	}
+}
+
+func TestCopyPatternMayMatchIn(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		dir      string
+		want     bool
+	}{
+		{[]string{".vscode/"}, ".vscode", true},
+		{[]string{"/node_modules/.cache/config.json"}, "node_modules", true},
+		{[]string{"node_modules/.cache/"}, "node_modules", true},
+		{[]string{".env.local"}, "node_modules", false},
+		{[]string{"*.local"}, "node_modules", false},
+		{[]string{"dist/app.js"}, "node_modules", false},
+	}
+	for _, tt := range tests {
+		matcher := newPatternMatcher(tt.patterns)
+		if got := copyPatternMayMatchIn(matcher, tt.patterns, tt.dir); got != tt.want {
+			t.Errorf("copyPatternMayMatchIn(%v, %q) = %v, want %v", tt.patterns, tt.dir, got, tt.want)
+		}
+	}
+}
@@ -120,18 +120,13 @@ func FindWorktreeByBranchOrDir(ctx context.Context, query string) (*Worktree, er
 		}
 	}
 
-	// Get worktree base directory for relative path comparison
-	cfg, err := LoadConfig(ctx)
-	if err != nil {
-		return nil, err
-	}
-	baseDir, err := ExpandBaseDir(ctx, cfg.BaseDir)
-	if err != nil {
-		return nil, err
-	}
-
-	// Then, try to find by directory name (relative path from base dir)
+	// Then, try to find by directory name (relative path from the base dir configured for its branch)
+	baseDirs := make(map[string]string)
 	for _, wt := range worktrees {
+		baseDir, err := branchBaseDir(ctx, wt.Branch, baseDirs)
+		if err != nil {
+			return nil, err
+		}
 		relPath, err := filepath.Rel(baseDir, wt.Path)
 		if err != nil {
 			continue
@@ -170,13 +165,9 @@ func FindWorktreeByBranchOrDir(ctx context.Context, query string) (*Worktree, er
 	return nil, nil
 }
 
-// WorktreeDirName returns the directory name of a worktree (relative path from base dir).
+// WorktreeDirName returns the directory name of a worktree (relative path from the base dir configured for its branch).
 func WorktreeDirName(ctx context.Context, wt *Worktree) (string, error) {
-	cfg, err := LoadConfig(ctx)
-	if err != nil {
-		return "", err
-	}
-	baseDir, err := ExpandBaseDir(ctx, cfg.BaseDir)
+	baseDir, err := branchBaseDir(ctx, wt.Branch, nil)
 	if err != nil {
 		return "", err
 	}
@@ -187,51 +178,65 @@ func WorktreeDirName(ctx context.Context, wt *Worktree) (string, error) {
 	return relPath, nil
 }
 
-// AddWorktree creates a new worktree for the given branch.
-func AddWorktree(ctx context.Context, path, branch string, copyOpts CopyOptions) error {
-	// Get source root before creating worktree
-	srcRoot, err := RepoRoot(ctx)
+// branchBaseDir returns the expanded base directory configured for branch.
+// Expanded base directories are memoized in cache by their pattern when cache is not nil.
+func branchBaseDir(ctx context.Context, branch string, cache map[string]string) (string, error) {
+	if branch == DetachedMarker {
+		branch = ""
+	}
+	cfg, err := LoadConfigFor(ctx, branch, "")
 	if err != nil {
-		return err
+		return "", err
 	}
-
-	// Ensure parent directory exists
-	parentDir := filepath.Dir(path)
-	if err := os.MkdirAll(parentDir, 0755); err != nil {
-		return fmt.Errorf("failed to create parent directory: %w", err)
+	if baseDir, ok := cache[cfg.BaseDir]; ok {
+		return baseDir, nil
 	}
-
-	if err := initBaseDir(parentDir); err != nil {
-		return err
+	baseDir, err := ExpandBaseDir(ctx, cfg.BaseDir)
+	if err != nil {
+		return "", err
+	}
+	if cache != nil {
+		cache[cfg.BaseDir] = baseDir
 	}
+	return baseDir, nil
+}
 
-	cmd, err := gitCommand(ctx, "worktree", "add", path, branch)
-	if err != nil {
+// AddWorktree creates a new worktree for the given branch.
+func AddWorktree(ctx context.Context, path, branch string, copyOpts CopyOptions) error {
+	return addWorktree(ctx, path, branch, []string{"worktree", "add", "--", path, branch}, copyOpts)
+}
+
+// AddWorktreeWithNewBranch creates a new worktree with a new branch.
+// If startPoint is specified, the new branch will be created from that commit/branch.
+// An invalid branch name is reported as an *InvalidBranchNameError before anything is created.
+func AddWorktreeWithNewBranch(ctx context.Context, path, branch, startPoint string, copyOpts CopyOptions) error {
+	// Validate the branch name and start point before creating directories
+	if err := ValidateBranchName(ctx, branch); err != nil {
 		return err
 	}
-	// Output git messages to stderr so stdout only contains the path for shell integration
-	cmd.Stdout = os.Stderr
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err := checkStartPoint(startPoint); err != nil {
 		return err
 	}
 
-	// Exclude basedir from copy to prevent circular copying
-	copyOpts.ExcludeDirs = append(copyOpts.ExcludeDirs, parentDir)
-
-	// Copy files to new worktree
-	if err := CopyFilesToWorktree(ctx, srcRoot, path, copyOpts); err != nil {
-		return fmt.Errorf("failed to copy files: %w", err)
+	// Build command arguments
+	args := []string{"worktree", "add", "-b", branch, "--", path}
+	if startPoint != "" {
+		args = append(args, startPoint)
 	}
+	return addWorktree(ctx, path, branch, args, copyOpts)
+}
 
-	return nil
+// AddWorktreeTrackingBranch creates a new worktree with a new local branch that tracks
+// the remote branch of the same name (e.g., feature tracking upstream/feature).
+func AddWorktreeTrackingBranch(ctx context.Context, path string, remoteBranch RemoteBranch, copyOpts CopyOptions) error {
+	args := []string{"worktree", "add", "--track", "-b", remoteBranch.Branch, "--", path, remoteBranch.String()}
+	return addWorktree(ctx, path, remoteBranch.Branch, args, copyOpts)
 }
 
-// AddWorktreeWithNewBranch creates a new worktree with a new branch.
-// If startPoint is specified, the new branch will be created from that commit/branch.
-func AddWorktreeWithNewBranch(ctx context.Context, path, branch, startPoint string, copyOpts CopyOptions) error {
+// addWorktree runs "git <args>" to create the worktree at path for branch, and copies files into it.
+func addWorktree(ctx context.Context, path, branch string, args []string, copyOpts CopyOptions) error {
 	// Get source root before creating worktree
-	srcRoot, err := RepoRoot(ctx)
+	srcRoot, err := copySourceRoot(ctx, copyOpts)
 	if err != nil {
 		return err
 	}
@@ -246,12 +251,6 @@ func AddWorktreeWithNewBranch(ctx context.Context, path, branch, startPoint stri
 		return err
 	}
 
-	// Build command arguments
-	args := []string{"worktree", "add", "-b", branch, path}
-	if startPoint != "" {
-		args = append(args, startPoint)
-	}
-
 	cmd, err := gitCommand(ctx, args...)
 	if err != nil {
 		return err
@@ -263,8 +262,20 @@ func AddWorktreeWithNewBranch(ctx context.Context, path, branch, startPoint stri
 		return err
 	}
 
-	// Exclude basedir from copy to prevent circular copying
-	copyOpts.ExcludeDirs = append(copyOpts.ExcludeDirs, parentDir)
+	// Exclude basedir from copy to prevent circular copying.
+	// Only needed when basedir is inside the source worktree; when the source worktree
+	// itself lives in basedir, excluding it would skip every file.
+	if _, ok := relativeWithin(srcRoot, parentDir); ok {
+		copyOpts.ExcludeDirs = append(copyOpts.ExcludeDirs, parentDir)
+	}
+
+	// Build template data for the new worktree
+	if len(copyOpts.Template) > 0 && copyOpts.TemplateData == nil {
+		copyOpts.TemplateData, err = NewTemplateData(ctx, path, branch)
+		if err != nil {
+			return fmt.Errorf("failed to build template data: %w", err)
+		}
+	}
 
 	// Copy files to new worktree
 	if err := CopyFilesToWorktree(ctx, srcRoot, path, copyOpts); err != nil {
@@ -274,6 +285,39 @@ func AddWorktreeWithNewBranch(ctx context.Context, path, branch, startPoint stri
 	return nil
 }
 
+// CopySourceMain is the wt.copyfrom value that selects the main worktree as the copy source.
+const CopySourceMain = "main"
+
+// ResolveCopySource resolves a copy source to a worktree root path.
+// The value can be CopySourceMain (the main worktree), or a branch name, worktree directory name
+// or path resolved via FindWorktreeByBranchOrDir. An empty value resolves to an empty path,
+// which means the current worktree.
+func ResolveCopySource(ctx context.Context, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if value == CopySourceMain {
+		return MainRepoRoot(ctx)
+	}
+	wt, err := FindWorktreeByBranchOrDir(ctx, value)
+	if err != nil {
+		return "", err
+	}
+	if wt == nil {
+		return "", fmt.Errorf("no worktree found for copy source %q", value)
+	}
+	return wt.Path, nil
+}
+
+// copySourceRoot returns the root of the worktree to copy files from.
+// It defaults to the current worktree when CopyOptions.SrcRoot is not set.
+func copySourceRoot(ctx context.Context, copyOpts CopyOptions) (string, error) {
+	if copyOpts.SrcRoot != "" {
+		return copyOpts.SrcRoot, nil
+	}
+	return RepoRoot(ctx)
+}
+
 // initBaseDir initializes the basedir with .gitignore and README.md files.
 // It creates these files only if they don't already exist.
 func initBaseDir(baseDir string) error {
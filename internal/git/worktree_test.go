@@ -222,6 +222,35 @@ func TestAddWorktreeWithNewBranch(t *testing.T) {
 	}
 }
 
+func TestAddWorktreeTrackingBranch(t *testing.T) {
+	upstream := testutil.NewTestRepo(t)
+	upstream.CreateFile("README.md", "# Upstream")
+	upstream.Commit("initial commit")
+	upstream.Git("branch", "feature")
+
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+	repo.Git("remote", "add", "upstream", upstream.Root)
+	repo.Git("fetch", "upstream")
+
+	restore := repo.Chdir()
+	defer restore()
+
+	wtPath := filepath.Join(repo.ParentDir(), "worktree-tracking")
+	err := AddWorktreeTrackingBranch(t.Context(), wtPath, RemoteBranch{Remote: "upstream", Branch: "feature"}, CopyOptions{})
+	if err != nil {
+		t.Fatalf("AddWorktreeTrackingBranch failed: %v", err)
+	}
+
+	if got := repo.Git("rev-parse", "--abbrev-ref", "feature@{upstream}"); got != "upstream/feature" {
+		t.Errorf("upstream of feature = %q, want %q", got, "upstream/feature")
+	}
+	if got, want := repo.Git("-C", wtPath, "rev-parse", "HEAD"), repo.Git("rev-parse", "upstream/feature"); got != want {
+		t.Errorf("worktree HEAD = %s, want %s", got, want)
+	}
+}
+
 func TestRemoveWorktree(t *testing.T) {
 	repo := testutil.NewTestRepo(t)
 	repo.CreateFile("README.md", "# Test")
@@ -291,3 +320,52 @@ func TestRemoveWorktree_Force(t *testing.T) {
 		t.Error("worktree should not exist after force removal")
 	}
 }
+
+func TestResolveCopySource(t *testing.T) {
+	repo := testutil.NewTestRepo(t)
+	repo.CreateFile("README.md", "# Test")
+	repo.Commit("initial commit")
+
+	// Create a worktree
+	wtPath := filepath.Join(repo.ParentDir(), "worktree-feature")
+	repo.Git("worktree", "add", "-b", "feature", wtPath)
+
+	// Run from inside the feature worktree
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+	if err := os.Chdir(wtPath); err != nil {
+		t.Fatalf("failed to chdir: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(origDir); err != nil {
+			t.Fatalf("failed to restore directory: %v", err)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"empty means current worktree", "", "", false},
+		{"main worktree", CopySourceMain, repo.Root, false},
+		{"by branch", "feature", wtPath, false},
+		{"by path", wtPath, wtPath, false},
+		{"not found", "no-such-worktree", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveCopySource(t.Context(), tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveCopySource(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveCopySource(%q) = %q, want %q", tt.value, got, tt.want) //nostyle:errorstrings
+			}
+		})
+	}
+}